@@ -2,12 +2,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	log "github.com/pion/ion-log"
 	biz "github.com/pion/ion/apps/biz/server"
+	"github.com/pion/ion/pkg/ion"
 	"github.com/spf13/viper"
 )
 
@@ -86,5 +90,16 @@ func main() {
 		os.Exit(-1)
 	}
 	defer node.Close()
-	select {}
+
+	// Press Ctrl+C to exit the process, SIGTERM drains the node first
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	if sig := <-ch; sig == syscall.SIGTERM {
+		log.Infof("--- draining biz node ---")
+		ctx, cancel := context.WithTimeout(context.Background(), ion.DefaultDrainTimeout)
+		defer cancel()
+		if err := node.Drain(ctx); err != nil {
+			log.Warnf("drain error: %v", err)
+		}
+	}
 }
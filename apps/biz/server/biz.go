@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
@@ -27,6 +28,10 @@ type natsConf struct {
 
 type nodeConf struct {
 	NID string `mapstructure:"nid"`
+	// Secret signs the node info, shared by every node of the cluster
+	Secret string `mapstructure:"secret"`
+	// Capacity the limits advertised to the islb
+	Capacity ion.Capacity `mapstructure:"capacity"`
 }
 
 // Config for biz node
@@ -69,6 +74,8 @@ func (b *BIZ) Start(conf Config) error {
 		b.Close()
 		return err
 	}
+	b.Node.SetSecret(conf.Node.Secret)
+	b.Node.SetCapacity(conf.Node.Capacity)
 
 	b.s, err = newBizServer(b, conf.Global.Dc, b.NID, b.NatsConn())
 	if err != nil {
@@ -111,9 +118,18 @@ func (b *BIZ) Start(conf Config) error {
 	return nil
 }
 
+// Drain stop taking new sessions and close the node once the peers are gone or ctx is done
+func (b *BIZ) Drain(ctx context.Context) error {
+	err := b.Node.Drain(ctx)
+	b.Close()
+	return err
+}
+
 // Close all
 func (b *BIZ) Close() {
-	b.s.close()
+	if b.s != nil {
+		b.s.close()
+	}
 	b.Node.Close()
 }
 
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"sync"
+
 	biz "github.com/pion/ion/apps/biz/proto"
 	"github.com/pion/ion/pkg/util"
 	"github.com/pion/ion/proto/ion"
@@ -8,12 +10,16 @@ import (
 
 // Peer represents a peer for client
 type Peer struct {
-	uid             string
-	sid             string
-	info            []byte
-	lastStreamEvent *ion.StreamEvent
-	closed          util.AtomicBool
-	sndCh           chan *biz.SignalReply
+	uid    string
+	sid    string
+	info   []byte
+	closed util.AtomicBool
+	sndCh  chan *biz.SignalReply
+
+	mu sync.Mutex
+	// the streams published by the peer on the sfu nid, from its stream events
+	nid     string
+	streams []*ion.Stream
 }
 
 func NewPeer(sid string, uid string, info []byte, senCh chan *biz.SignalReply) *Peer {
@@ -54,6 +60,31 @@ func (p *Peer) send(data *biz.SignalReply) error {
 	return nil
 }
 
+// updateStreams apply a stream event of the peer to its streams
+func (p *Peer) updateStreams(event *ion.StreamEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nid = event.Nid
+	p.streams = util.ApplyStreamEvent(p.streams, event.State, event.Streams)
+}
+
+// streamEvent return the ADD event of the streams published by the peer,
+// nil if none
+func (p *Peer) streamEvent() *ion.StreamEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.streams) == 0 {
+		return nil
+	}
+	return &ion.StreamEvent{
+		State:   ion.StreamEvent_ADD,
+		Nid:     p.nid,
+		Sid:     p.sid,
+		Uid:     p.uid,
+		Streams: p.streams,
+	}
+}
+
 func (p *Peer) sendPeerEvent(event *ion.PeerEvent) error {
 	data := &biz.SignalReply{
 		Payload: &biz.SignalReply_PeerEvent{
@@ -59,8 +59,8 @@ func (r *Room) addPeer(p *Peer) {
 			log.Errorf("p.sendPeerEvent() failed %v", err)
 		}
 
-		if peer.lastStreamEvent != nil {
-			err := p.sendStreamEvent(peer.lastStreamEvent)
+		if event := peer.streamEvent(); event != nil {
+			err := p.sendStreamEvent(event)
 			if err != nil {
 				log.Errorf("p.sendStreamEvent() failed %v", err)
 			}
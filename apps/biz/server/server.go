@@ -8,9 +8,11 @@ import (
 	"time"
 
 	ndc "github.com/cloudwebrtc/nats-discovery/pkg/client"
+	nrpc "github.com/cloudwebrtc/nats-grpc/pkg/rpc"
 	"github.com/nats-io/nats.go"
 	log "github.com/pion/ion-log"
 	biz "github.com/pion/ion/apps/biz/proto"
+	"github.com/pion/ion/pkg/ion"
 	"github.com/pion/ion/pkg/proto"
 	"github.com/pion/ion/pkg/util"
 	islb "github.com/pion/ion/proto/islb"
@@ -27,9 +29,24 @@ type BizServer struct {
 	ndc      *ndc.Client
 	islbcli  islb.ISLBClient
 	bn       *BIZ
+	islbLock sync.Mutex
+	// the pooled client of islbcli, released when the stream breaks or the server closes
+	islbconn *nrpc.Client
 	stream   islb.ISLB_WatchISLBEventClient
+	// dc of the biz node, its rooms go to the sfus of this dc first
+	dc string
+	// the last islb event received, a broken stream resumes after it
+	// if it reconnects to the islb that numbered it
+	seqLock sync.Mutex
+	islbSeq uint64
+	islbID  string
 }
 
+const (
+	// delay before reopening a broken islb stream
+	rewatchDelay = time.Second
+)
+
 // newBizServer creates a new avp server instance
 func newBizServer(bn *BIZ, c string, nid string, nc *nats.Conn) (*BizServer, error) {
 
@@ -47,13 +64,30 @@ func newBizServer(bn *BIZ, c string, nid string, nc *nats.Conn) (*BizServer, err
 		rooms:  make(map[string]*Room),
 		closed: make(chan struct{}),
 		stream: nil,
+		dc:     c,
 	}
 
 	return b, nil
 }
 
 func (s *BizServer) close() {
-	close(s.closed)
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
+	s.islbLock.Lock()
+	s.releaseISLBClient()
+	s.islbLock.Unlock()
+}
+
+// releaseISLBClient drop the client to the islb, the next watch picks an
+// islb again, s.islbLock is held
+func (s *BizServer) releaseISLBClient() {
+	if s.islbconn != nil {
+		s.bn.Release(s.islbconn)
+	}
+	s.islbcli, s.islbconn = nil, nil
 }
 
 func (s *BizServer) createRoom(sid string, sfuNID string) *Room {
@@ -82,36 +116,47 @@ func (s *BizServer) delRoom(r *Room) {
 	}
 }
 
-func (s *BizServer) watchISLBEvent(nid string, sid string) error {
-	//s.islbLock.Lock()
-	//defer s.islbLock.Unlock()
+// watchISLBEvent subscribe to the stream events of session sid, resumeAfter
+// is the seq of the last event received and resumeIslb the islb numbering
+// it, 0 to get the current streams
+func (s *BizServer) watchISLBEvent(nid string, sid string, resumeAfter uint64, resumeIslb string) error {
+	s.islbLock.Lock()
+	defer s.islbLock.Unlock()
 
 	if s.islbcli == nil {
 		ncli, err := s.bn.NewNatsRPCClient(proto.ServiceISLB, nid, map[string]interface{}{})
 		if err != nil {
 			return err
 		}
-		s.islbcli = islb.NewISLBClient(ncli)
+		s.islbcli, s.islbconn = islb.NewISLBClient(ncli), ncli
 	}
 
 	if s.stream == nil && s.islbcli != nil {
 		stream, err := s.islbcli.WatchISLBEvent(context.Background())
 		if err != nil {
+			s.releaseISLBClient()
 			return err
 		}
 		err = stream.Send(&islb.WatchRequest{
-			Nid: nid,
-			Sid: sid,
+			Nid:         nid,
+			Sid:         sid,
+			ResumeAfter: resumeAfter,
+			ResumeIslb:  resumeIslb,
 		})
 		if err != nil {
+			s.releaseISLBClient()
 			return err
 		}
 
 		go func() {
 			defer func() {
-				//s.islbLock.Lock()
-				//defer s.islbLock.Unlock()
-				s.stream = nil
+				s.islbLock.Lock()
+				defer s.islbLock.Unlock()
+				if s.stream == stream {
+					s.stream = nil
+					s.releaseISLBClient()
+					go s.rewatchISLBEvent()
+				}
 			}()
 
 			for {
@@ -121,15 +166,24 @@ func (s *BizServer) watchISLBEvent(nid string, sid string) error {
 					return
 				}
 				log.Infof("watchISLBEvent req => %v", req)
+				s.seqLock.Lock()
+				if req.Islb != s.islbID || req.Seq > s.islbSeq {
+					s.islbSeq, s.islbID = req.Seq, req.Islb
+				}
+				s.seqLock.Unlock()
 				switch payload := req.Payload.(type) {
 				case *islb.ISLBEvent_Stream:
+					if origin := payload.Stream.Origin; origin != "" && origin != payload.Stream.Nid {
+						// a copy for a cascaded sfu, the peers got the original
+						continue
+					}
 					r := s.getRoom(payload.Stream.Sid)
 					if r != nil {
 						r.sendStreamEvent(payload.Stream)
 						p := r.getPeer(payload.Stream.Uid)
-						// save last stream info.
+						// keep the streams for the peers joining later
 						if p != nil {
-							p.lastStreamEvent = payload.Stream
+							p.updateStreams(payload.Stream)
 						}
 					}
 				}
@@ -137,12 +191,58 @@ func (s *BizServer) watchISLBEvent(nid string, sid string) error {
 		}()
 
 		s.stream = stream
+		return nil
+	}
+
+	// the islb only forwards the events of the sessions watched by the stream.
+	if s.stream != nil {
+		return s.stream.Send(&islb.WatchRequest{
+			Nid:         nid,
+			Sid:         sid,
+			ResumeAfter: resumeAfter,
+			ResumeIslb:  resumeIslb,
+		})
 	}
 	return nil
 }
 
+// rewatchISLBEvent reopen the broken islb stream and resume the events of every room
+func (s *BizServer) rewatchISLBEvent() {
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-time.After(rewatchDelay):
+		}
+
+		s.roomLock.RLock()
+		rooms := make([]*Room, 0, len(s.rooms))
+		for _, r := range s.rooms {
+			rooms = append(rooms, r)
+		}
+		s.roomLock.RUnlock()
+
+		var err error
+		s.seqLock.Lock()
+		seq, id := s.islbSeq, s.islbID
+		s.seqLock.Unlock()
+		for _, r := range rooms {
+			if err = s.watchISLBEvent(r.nid, r.SID(), seq, id); err != nil {
+				log.Errorf("s.watchISLBEvent(%v) failed %v", r.SID(), err)
+				break
+			}
+		}
+		if err == nil {
+			return
+		}
+	}
+}
+
 //Signal process biz request.
 func (s *BizServer) Signal(stream biz.Biz_SignalServer) error {
+	s.bn.StreamStarted()
+	defer s.bn.StreamEnded()
+
 	var r *Room = nil
 	var peer *Peer = nil
 	errCh := make(chan error)
@@ -202,20 +302,26 @@ func (s *BizServer) Signal(stream biz.Biz_SignalServer) error {
 				reason := "unkown error."
 				r = s.getRoom(sid)
 
-				if r == nil {
-					reason = fmt.Sprintf("room sid = %v not found", sid)
-					resp, err := s.ndc.Get(proto.ServiceSFU, map[string]interface{}{"sid": sid, "uid": uid})
-					if err != nil {
-						log.Errorf("dnc.Get: serivce = %v error %v", proto.ServiceSFU, err)
-					}
-					nid := ""
-					if err == nil && len(resp.Nodes) > 0 {
-						nid = resp.Nodes[0].NID
+				// the islb picks the sfu of every peer, the one hosting
+				// the session or an sfu the session is cascaded to.
+				nid := ""
+				resp, err := s.ndc.Get(proto.ServiceSFU, map[string]interface{}{"sid": sid, "uid": uid, "caller_dc": s.dc})
+				if err != nil {
+					log.Errorf("dnc.Get: serivce = %v error %v", proto.ServiceSFU, err)
+				}
+				if err == nil && len(resp.Nodes) > 0 {
+					nid = resp.Nodes[0].NID
+					if r == nil {
 						r = s.createRoom(sid, nid)
-						err = s.watchISLBEvent(nid, sid)
+						err = s.watchISLBEvent(nid, sid, 0, "")
 						if err != nil {
 							log.Errorf("s.watchISLBEvent(req) failed %v", err)
 						}
+					}
+				} else {
+					r = nil
+					if ion.IsClusterFull(err) {
+						reason = ion.ErrClusterFull.Error()
 					} else {
 						reason = "get serivce [sfu], node cnt == 0"
 					}
@@ -228,7 +334,7 @@ func (s *BizServer) Signal(stream biz.Biz_SignalServer) error {
 					reason = "join success."
 
 					//Generate necessary metadata for routing.
-					header := metadata.New(map[string]string{"service": "sfu", "nid": r.nid, "sid": sid, "uid": uid})
+					header := metadata.New(map[string]string{"service": "sfu", "nid": nid, "sid": sid, "uid": uid})
 					err := stream.SendHeader(header)
 					if err != nil {
 						log.Errorf("stream.SendHeader failed %v", err)
@@ -284,7 +390,7 @@ func (s *BizServer) Signal(stream biz.Biz_SignalServer) error {
 	}
 }
 
-// stat peers
+// stat peers, and publish them as the load of the node
 func (s *BizServer) stat() {
 	t := time.NewTicker(util.DefaultStatCycle)
 	defer t.Stop()
@@ -297,11 +403,16 @@ func (s *BizServer) stat() {
 		}
 
 		var info string
+		peers := 0
 		s.roomLock.RLock()
 		for sid, room := range s.rooms {
-			info += fmt.Sprintf("room: %s\npeers: %d\n", sid, room.count())
+			count := room.count()
+			peers += count
+			info += fmt.Sprintf("room: %s\npeers: %d\n", sid, count)
 		}
+		sessions := len(s.rooms)
 		s.roomLock.RUnlock()
+		s.bn.UpdateLoad(ion.Load{Sessions: sessions, Peers: peers})
 		if len(info) > 0 {
 			log.Infof("\n----------------signal-----------------\n" + info)
 		}
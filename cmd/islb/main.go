@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	_ "net/http/pprof"
@@ -9,6 +10,7 @@ import (
 	"syscall"
 
 	log "github.com/pion/ion-log"
+	"github.com/pion/ion/pkg/ion"
 	"github.com/pion/ion/pkg/node/islb"
 	"github.com/spf13/viper"
 )
@@ -79,8 +81,15 @@ func main() {
 	}
 	defer node.Close()
 
-	// Press Ctrl+C to exit the process
+	// Press Ctrl+C to exit the process, SIGTERM drains the node first
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
-	<-ch
+	if sig := <-ch; sig == syscall.SIGTERM {
+		log.Infof("--- draining islb node ---")
+		ctx, cancel := context.WithTimeout(context.Background(), ion.DefaultDrainTimeout)
+		defer cancel()
+		if err := node.Drain(ctx); err != nil {
+			log.Warnf("drain error: %v", err)
+		}
+	}
 }
@@ -2,14 +2,18 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	ossignal "os/signal"
+	"syscall"
 
 	nrpc "github.com/cloudwebrtc/nats-grpc/pkg/rpc"
 	nproxy "github.com/cloudwebrtc/nats-grpc/pkg/rpc/proxy"
 	log "github.com/pion/ion-log"
 	"github.com/pion/ion/cmd/signal/server"
+	"github.com/pion/ion/pkg/ion"
 	"github.com/pion/ion/pkg/node/signal"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
@@ -110,8 +114,21 @@ func main() {
 		grpc.UnknownServiceHandler(nproxy.TransparentHandler(sig.Director)))
 
 	s := server.NewWrapperedGRPCWebServer(options, srv)
-	if err := s.Serve(); err != nil {
-		log.Panicf("failed to serve: %v", err)
+	go func() {
+		if err := s.Serve(); err != nil {
+			log.Panicf("failed to serve: %v", err)
+		}
+	}()
+
+	// Press Ctrl+C to exit the process, SIGTERM drains the node first
+	ch := make(chan os.Signal, 1)
+	ossignal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	if osSig := <-ch; osSig == syscall.SIGTERM {
+		log.Infof("--- draining signal node ---")
+		ctx, cancel := context.WithTimeout(context.Background(), ion.DefaultDrainTimeout)
+		defer cancel()
+		if err := sig.Drain(ctx); err != nil {
+			log.Warnf("drain error: %v", err)
+		}
 	}
-	select {}
 }
@@ -14,7 +14,6 @@ require (
 	github.com/pion/ion-log v1.2.0
 	github.com/pion/ion-sfu v1.10.6
 	github.com/pion/webrtc/v3 v3.0.29
-	github.com/pixelbender/go-sdp v1.1.0
 	github.com/soheilhy/cmux v0.1.4
 	github.com/spf13/viper v1.7.1
 	github.com/square/go-jose/v3 v3.0.0-20200630053402-0a67ce9b0693
@@ -0,0 +1,386 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/pion/ion-log"
+)
+
+const (
+	memoryExpireCheck = time.Second
+)
+
+type memoryEntry struct {
+	value  string
+	hash   map[string]string
+	expire time.Time
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expire.IsZero() && !now.Before(e.expire)
+}
+
+type memoryWatch struct {
+	pattern *regexp.Regexp
+	ch      chan Event
+}
+
+// Memory is an in-process Store, for tests and single islb deployments without redis.
+// Keys expire like redis keys, and watchers get redis style keyspace notifications.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	watches map[*memoryWatch]struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewMemory create an in-memory store
+func NewMemory() *Memory {
+	m := &Memory{
+		entries: make(map[string]*memoryEntry),
+		watches: make(map[*memoryWatch]struct{}),
+		done:    make(chan struct{}),
+	}
+	go m.expireLoop()
+	return m
+}
+
+func (m *Memory) Close() {
+	m.once.Do(func() {
+		close(m.done)
+	})
+}
+
+// expireLoop drop the expired keys, so their watchers are told even if nobody reads them.
+func (m *Memory) expireLoop() {
+	t := time.NewTicker(memoryExpireCheck)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-m.done:
+			return
+		}
+		m.mu.Lock()
+		now := time.Now()
+		for k, e := range m.entries {
+			if e.expired(now) {
+				delete(m.entries, k)
+				m.notify(k, OpExpired)
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+// get return the live entry of k, m.mu must be held
+func (m *Memory) get(k string) *memoryEntry {
+	e, found := m.entries[k]
+	if !found {
+		return nil
+	}
+	if e.expired(time.Now()) {
+		delete(m.entries, k)
+		m.notify(k, OpExpired)
+		return nil
+	}
+	return e
+}
+
+// notify send op to the watchers of k, m.mu must be held
+func (m *Memory) notify(k string, op Op) {
+	for w := range m.watches {
+		if !w.pattern.MatchString(k) {
+			continue
+		}
+		event := Event{Key: k, Op: op}
+		if op == OpSet {
+			event.Value = m.entries[k].value
+		}
+		select {
+		case w.ch <- event:
+		default:
+			log.Warnf("memory store watcher too slow, drop %v on %v", op, k)
+		}
+	}
+}
+
+func (m *Memory) Set(k, v string, t time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := &memoryEntry{value: v}
+	if t > 0 {
+		e.expire = time.Now().Add(t)
+	}
+	m.entries[k] = e
+	m.notify(k, OpSet)
+	return nil
+}
+
+func (m *Memory) SetNX(k, v string, t time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.get(k) != nil {
+		return false, nil
+	}
+	e := &memoryEntry{value: v}
+	if t > 0 {
+		e.expire = time.Now().Add(t)
+	}
+	m.entries[k] = e
+	m.notify(k, OpSet)
+	return true, nil
+}
+
+func (m *Memory) Incr(k string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.get(k)
+	if e == nil {
+		e = &memoryEntry{value: "0"}
+		m.entries[k] = e
+	}
+	if e.hash != nil {
+		return 0, fmt.Errorf("WRONGTYPE %v is not a string", k)
+	}
+	n, err := strconv.ParseInt(e.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value of %v is not an integer", k)
+	}
+	n++
+	e.value = strconv.FormatInt(n, 10)
+	m.notify(k, "incrby")
+	return n, nil
+}
+
+func (m *Memory) Get(k string) interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e := m.get(k); e != nil && e.hash == nil {
+		return e.value
+	}
+	return ""
+}
+
+func (m *Memory) HSet(k, field string, value interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hset(k, field, value)
+}
+
+func (m *Memory) hset(k, field string, value interface{}) error {
+	e := m.get(k)
+	if e == nil {
+		e = &memoryEntry{hash: make(map[string]string)}
+		m.entries[k] = e
+	}
+	if e.hash == nil {
+		return fmt.Errorf("WRONGTYPE %v is not a hash", k)
+	}
+	e.hash[field] = fmt.Sprint(value)
+	m.notify(k, "hset")
+	return nil
+}
+
+func (m *Memory) HGet(k, field string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e := m.get(k); e != nil {
+		return e.hash[field]
+	}
+	return ""
+}
+
+func (m *Memory) HGetAll(k string) map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fields := make(map[string]string)
+	if e := m.get(k); e != nil {
+		for f, v := range e.hash {
+			fields[f] = v
+		}
+	}
+	return fields
+}
+
+func (m *Memory) HDel(k, field string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.get(k)
+	if e == nil || e.hash == nil {
+		return nil
+	}
+	delete(e.hash, field)
+	m.notify(k, "hdel")
+	if len(e.hash) == 0 {
+		delete(m.entries, k)
+		m.notify(k, OpDel)
+	}
+	return nil
+}
+
+func (m *Memory) Expire(k string, t time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.expire(k, t)
+}
+
+func (m *Memory) expire(k string, t time.Duration) error {
+	e := m.get(k)
+	if e == nil {
+		return nil
+	}
+	e.expire = time.Now().Add(t)
+	m.notify(k, "expire")
+	return nil
+}
+
+func (m *Memory) HSetTTL(k, field string, value interface{}, t time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.hset(k, field, value); err != nil {
+		return err
+	}
+	return m.expire(k, t)
+}
+
+func (m *Memory) HIncrTTL(k, field string, n int64, t time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var value int64
+	if e := m.get(k); e != nil && e.hash != nil && e.hash[field] != "" {
+		var err error
+		if value, err = strconv.ParseInt(e.hash[field], 10, 64); err != nil {
+			return 0, fmt.Errorf("field %v of %v is not an integer", field, k)
+		}
+	}
+	value += n
+	if err := m.hset(k, field, value); err != nil {
+		return 0, err
+	}
+	return value, m.expire(k, t)
+}
+
+func (m *Memory) Keys(k string) []string {
+	pattern, err := globToRegexp(k)
+	if err != nil {
+		log.Errorf("memory keys %v err => %v", k, err)
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	now := time.Now()
+	for key, e := range m.entries {
+		if !e.expired(now) && pattern.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (m *Memory) MGet(keys []string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		if e := m.get(k); e != nil && e.hash == nil {
+			values[i] = e.value
+		}
+	}
+	return values
+}
+
+func (m *Memory) MSet(kv map[string]string, t time.Duration) error {
+	for k, v := range kv {
+		if err := m.Set(k, v, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Del(k string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.get(k) != nil {
+		delete(m.entries, k)
+		m.notify(k, OpDel)
+	}
+	return nil
+}
+
+// Watch send the changes of the keys matching key until ctx is done,
+// the events are dropped while the channel is full.
+func (m *Memory) Watch(ctx context.Context, key string) (<-chan Event, error) {
+	pattern, err := globToRegexp(key)
+	if err != nil {
+		return nil, err
+	}
+	w := &memoryWatch{
+		pattern: pattern,
+		ch:      make(chan Event, watchBuffer),
+	}
+	m.mu.Lock()
+	m.watches[w] = struct{}{}
+	m.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-m.done:
+		}
+		m.mu.Lock()
+		delete(m.watches, w)
+		close(w.ch)
+		m.mu.Unlock()
+	}()
+
+	return w.ch, nil
+}
+
+// globToRegexp translate a redis glob pattern (*, ?, [...] and \ escapes) to a regexp
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			j := strings.IndexByte(pattern[i:], ']')
+			if j <= 1 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+j]
+			if strings.HasPrefix(class, "^") {
+				class = "^" + regexp.QuoteMeta(class[1:])
+			} else {
+				class = regexp.QuoteMeta(class)
+			}
+			// keep the ranges of the class
+			b.WriteString("[" + strings.ReplaceAll(class, `\-`, "-") + "]")
+			i += j
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
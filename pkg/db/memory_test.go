@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryKV(t *testing.T) {
+	m := NewMemory()
+	defer m.Close()
+
+	assert.NoError(t, m.Set("dc1/sfu-01/room1/peer1", "[]", 0))
+	assert.NoError(t, m.Set("dc1/sfu-01/room2/peer2", "[]", 0))
+	assert.NoError(t, m.Set("dc1/sfu-02/room3/peer3", "[]", 0))
+	assert.Equal(t, "[]", m.Get("dc1/sfu-01/room1/peer1"))
+	assert.Equal(t, "", m.Get("dc1/sfu-01/room1/peer9"))
+
+	keys := m.Keys("dc1/sfu-01/*")
+	sort.Strings(keys)
+	assert.Equal(t, []string{"dc1/sfu-01/room1/peer1", "dc1/sfu-01/room2/peer2"}, keys)
+	assert.Len(t, m.Keys("dc1/*/room[13]/*"), 2)
+	assert.Len(t, m.Keys("dc1/sfu-0?/*"), 3)
+
+	// a bad pattern matches nothing, the ids of the clients match themselves
+	assert.Empty(t, m.Keys("dc1/*/[z-a]/*"))
+	assert.NoError(t, m.Set("dc1/sfu-01/[z-a]*/peer4", "[]", 0))
+	assert.Equal(t, []string{"dc1/sfu-01/[z-a]*/peer4"}, m.Keys("dc1/*/"+EscapeGlob("[z-a]*")+"/*"))
+	assert.NoError(t, m.Del("dc1/sfu-01/[z-a]*/peer4"))
+
+	assert.Equal(t, []string{"[]", "", "[]"}, m.MGet([]string{"dc1/sfu-01/room1/peer1", "dc1/sfu-01/room1/peer9", "dc1/sfu-02/room3/peer3"}))
+	assert.NoError(t, m.MSet(map[string]string{"a": "1", "b": "2"}, 0))
+	assert.Equal(t, []string{"1", "2"}, m.MGet([]string{"a", "b"}))
+
+	assert.NoError(t, m.Del("dc1/sfu-01/room1/peer1"))
+	assert.Len(t, m.Keys("dc1/*"), 2)
+
+	assert.NoError(t, m.HSetTTL("ion-session/dc1/room1", "nid", "sfu-01", time.Hour))
+	assert.NoError(t, m.HSet("ion-session/dc1/room1", "peers", 2))
+	assert.Equal(t, "2", m.HGet("ion-session/dc1/room1", "peers"))
+	assert.Equal(t, map[string]string{"nid": "sfu-01", "peers": "2"}, m.HGetAll("ion-session/dc1/room1"))
+	assert.NoError(t, m.HDel("ion-session/dc1/room1", "peers"))
+	assert.Equal(t, map[string]string{"nid": "sfu-01"}, m.HGetAll("ion-session/dc1/room1"))
+	assert.Error(t, m.HSet("dc1/sfu-02/room3/peer3", "nid", "sfu-02"))
+
+	ok, err := m.SetNX("ion-claim/room1", "islb-01", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = m.SetNX("ion-claim/room1", "islb-02", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "islb-01", m.Get("ion-claim/room1"))
+
+	n, err := m.Incr("ion-seq")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	n, _ = m.Incr("ion-seq")
+	assert.Equal(t, int64(2), n)
+	_, err = m.Incr("ion-session/dc1/room1")
+	assert.Error(t, err)
+
+	n, err = m.HIncrTTL("ion-admit/dc1/sfu-01", "peers", 2, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	n, _ = m.HIncrTTL("ion-admit/dc1/sfu-01", "peers", 1, time.Hour)
+	assert.Equal(t, int64(3), n)
+	_, err = m.HIncrTTL("ion-seq", "peers", 1, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestMemoryExpire(t *testing.T) {
+	m := NewMemory()
+	defer m.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := m.Watch(ctx, "ion-node/*")
+	assert.NoError(t, err)
+	_, err = m.Watch(ctx, "ion-node/[z-a]")
+	assert.Error(t, err)
+
+	assert.NoError(t, m.Set("ion-node/dc1/sfu/sfu-01", "{}", 50*time.Millisecond))
+	assert.NoError(t, m.Set("dc1/sfu-01/room1/peer1", "[]", 50*time.Millisecond))
+	assert.Equal(t, Event{Key: "ion-node/dc1/sfu/sfu-01", Op: OpSet, Value: "{}"}, <-ch)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "", m.Get("dc1/sfu-01/room1/peer1"))
+	assert.Empty(t, m.Keys("ion-node/*"))
+
+	select {
+	case event := <-ch:
+		assert.Equal(t, Event{Key: "ion-node/dc1/sfu/sfu-01", Op: OpExpired}, event)
+	case <-time.After(2 * memoryExpireCheck):
+		t.Fatal("no expired notification")
+	}
+
+	cancel()
+	for range ch {
+	}
+}
@@ -2,7 +2,12 @@ package db
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,58 +17,161 @@ import (
 )
 
 type Config struct {
-	Addrs []string `mapstructure:"addrs"`
-	Pwd   string   `mapstructure:"password"`
-	DB    int      `mapstructure:"db"`
+	// Addrs a single redis, or the nodes of a redis cluster
+	Addrs    []string `mapstructure:"addrs"`
+	Username string   `mapstructure:"username"`
+	Pwd      string   `mapstructure:"password"`
+	DB       int      `mapstructure:"db"`
+
+	// MasterName and SentinelAddrs select a sentinel managed redis, Addrs is then unused
+	MasterName    string   `mapstructure:"master_name"`
+	SentinelAddrs []string `mapstructure:"sentinel_addrs"`
+
+	// TLS connect over tls, CA verifies the server (system roots if empty),
+	// Cert and Key are the client certificate, if the server asks for one
+	TLS  bool   `mapstructure:"tls"`
+	CA   string `mapstructure:"ca"`
+	Cert string `mapstructure:"cert"`
+	Key  string `mapstructure:"key"`
+
+	// ConnectRetries number of retries when redis is unreachable at startup, -1 retries forever
+	ConnectRetries int `mapstructure:"connect_retries"`
 }
 
+// Redis is a Store backed by a single redis, a sentinel managed redis or a redis cluster,
+// the clients are safe for concurrent use.
 type Redis struct {
 	cluster     *redis.ClusterClient
 	single      *redis.Client
 	clusterMode bool
-	mutex       *sync.Mutex
 }
 
-func NewRedis(c Config) *Redis {
-	if len(c.Addrs) == 0 {
-		return nil
+const (
+	// keys asked per SCAN iteration
+	scanCount = 1000
+
+	connectRetryMin = time.Second
+	connectRetryMax = 30 * time.Second
+)
+
+// NewRedis connect to redis, retrying c.ConnectRetries times with backoff if it is unreachable
+func NewRedis(c Config) (*Redis, error) {
+	tlsConfig, err := newTLSConfig(c)
+	if err != nil {
+		return nil, err
 	}
 
 	r := &Redis{}
-	if len(c.Addrs) == 1 {
+	switch {
+	case c.MasterName != "":
+		if len(c.SentinelAddrs) == 0 {
+			return nil, errors.New("redis sentinel_addrs is empty")
+		}
+		r.single = redis.NewFailoverClient(
+			&redis.FailoverOptions{
+				MasterName:    c.MasterName,
+				SentinelAddrs: c.SentinelAddrs,
+				Username:      c.Username,
+				Password:      c.Pwd,
+				DB:            c.DB,
+				TLSConfig:     tlsConfig,
+				DialTimeout:   3 * time.Second,
+				ReadTimeout:   5 * time.Second,
+				WriteTimeout:  5 * time.Second,
+			})
+	case len(c.Addrs) == 1:
 		r.single = redis.NewClient(
 			&redis.Options{
 				Addr:         c.Addrs[0], // use default Addr
-				Password:     c.Pwd,      // no password set
-				DB:           c.DB,       // use default DB
+				Username:     c.Username,
+				Password:     c.Pwd, // no password set
+				DB:           c.DB,  // use default DB
+				TLSConfig:    tlsConfig,
 				DialTimeout:  3 * time.Second,
 				ReadTimeout:  5 * time.Second,
 				WriteTimeout: 5 * time.Second,
 			})
-		if err := r.single.Ping().Err(); err != nil {
-			log.Errorf(err.Error())
+	case len(c.Addrs) > 1:
+		r.cluster = redis.NewClusterClient(
+			&redis.ClusterOptions{
+				Addrs:        c.Addrs,
+				Username:     c.Username,
+				Password:     c.Pwd,
+				TLSConfig:    tlsConfig,
+				DialTimeout:  3 * time.Second,
+				ReadTimeout:  5 * time.Second,
+				WriteTimeout: 5 * time.Second,
+			})
+		r.clusterMode = true
+	default:
+		return nil, errors.New("redis addrs is empty")
+	}
+
+	if err := r.ping(c.ConnectRetries); err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	// managed redis services usually refuse CONFIG, Watch checks the flags set on the server.
+	if r.clusterMode {
+		err = r.cluster.ConfigSet("notify-keyspace-events", "AKE").Err()
+	} else {
+		err = r.single.ConfigSet("notify-keyspace-events", "AKE").Err()
+	}
+	if err != nil {
+		log.Warnf("redis enable keyspace notifications: %v", err)
+	}
+	return r, nil
+}
+
+// ping redis until it answers, retries times with an exponential backoff
+func (r *Redis) ping(retries int) error {
+	delay := connectRetryMin
+	for i := 0; ; i++ {
+		var err error
+		if r.clusterMode {
+			err = r.cluster.Ping().Err()
+		} else {
+			err = r.single.Ping().Err()
+		}
+		if err == nil {
 			return nil
 		}
-		r.single.Do("CONFIG", "SET", "notify-keyspace-events", "AKE")
-		r.clusterMode = false
-		r.mutex = new(sync.Mutex)
-		return r
+		if retries >= 0 && i >= retries {
+			return fmt.Errorf("redis unreachable: %v", err)
+		}
+		log.Warnf("redis unreachable: %v, retry in %v", err, delay)
+		time.Sleep(delay)
+		if delay *= 2; delay > connectRetryMax {
+			delay = connectRetryMax
+		}
 	}
+}
 
-	r.cluster = redis.NewClusterClient(
-		&redis.ClusterOptions{
-			Addrs:        c.Addrs,
-			Password:     c.Pwd,
-			DialTimeout:  3 * time.Second,
-			ReadTimeout:  5 * time.Second,
-			WriteTimeout: 5 * time.Second,
-		})
-	if err := r.cluster.Ping().Err(); err != nil {
-		log.Errorf(err.Error())
+func newTLSConfig(c Config) (*tls.Config, error) {
+	if !c.TLS {
+		return nil, nil
 	}
-	r.cluster.Do("CONFIG", "SET", "notify-keyspace-events", "AKE")
-	r.clusterMode = true
-	return r
+
+	config := &tls.Config{}
+	if c.CA != "" {
+		pem, err := ioutil.ReadFile(c.CA)
+		if err != nil {
+			return nil, fmt.Errorf("read redis ca: %v", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate in redis ca %v", c.CA)
+		}
+	}
+	if c.Cert != "" || c.Key != "" {
+		cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+		if err != nil {
+			return nil, fmt.Errorf("load redis cert: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
 }
 
 func (r *Redis) Close() {
@@ -76,17 +184,27 @@ func (r *Redis) Close() {
 }
 
 func (r *Redis) Set(k, v string, t time.Duration) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	if r.clusterMode {
 		return r.cluster.Set(k, v, t).Err()
 	}
 	return r.single.Set(k, v, t).Err()
 }
 
+func (r *Redis) SetNX(k, v string, t time.Duration) (bool, error) {
+	if r.clusterMode {
+		return r.cluster.SetNX(k, v, t).Result()
+	}
+	return r.single.SetNX(k, v, t).Result()
+}
+
+func (r *Redis) Incr(k string) (int64, error) {
+	if r.clusterMode {
+		return r.cluster.Incr(k).Result()
+	}
+	return r.single.Incr(k).Result()
+}
+
 func (r *Redis) Get(k string) interface{} {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	if r.clusterMode {
 		return r.cluster.Get(k).Val()
 	}
@@ -94,8 +212,6 @@ func (r *Redis) Get(k string) interface{} {
 }
 
 func (r *Redis) HSet(k, field string, value interface{}) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	if r.clusterMode {
 		return r.cluster.HSet(k, field, value).Err()
 	}
@@ -103,8 +219,6 @@ func (r *Redis) HSet(k, field string, value interface{}) error {
 }
 
 func (r *Redis) HGet(k, field string) string {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	if r.clusterMode {
 		return r.cluster.HGet(k, field).Val()
 	}
@@ -112,8 +226,6 @@ func (r *Redis) HGet(k, field string) string {
 }
 
 func (r *Redis) HGetAll(k string) map[string]string {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	if r.clusterMode {
 		return r.cluster.HGetAll(k).Val()
 	}
@@ -121,8 +233,6 @@ func (r *Redis) HGetAll(k string) map[string]string {
 }
 
 func (r *Redis) HDel(k, field string) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	if r.clusterMode {
 		return r.cluster.HDel(k, field).Err()
 	}
@@ -130,8 +240,6 @@ func (r *Redis) HDel(k, field string) error {
 }
 
 func (r *Redis) Expire(k string, t time.Duration) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	if r.clusterMode {
 		return r.cluster.Expire(k, t).Err()
 	}
@@ -140,8 +248,6 @@ func (r *Redis) Expire(k string, t time.Duration) error {
 }
 
 func (r *Redis) HSetTTL(k, field string, value interface{}, t time.Duration) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	if r.clusterMode {
 		if err := r.cluster.HSet(k, field, value).Err(); err != nil {
 			return err
@@ -154,48 +260,250 @@ func (r *Redis) HSetTTL(k, field string, value interface{}, t time.Duration) err
 	return r.single.Expire(k, t).Err()
 }
 
+func (r *Redis) HIncrTTL(k, field string, n int64, t time.Duration) (int64, error) {
+	if r.clusterMode {
+		value, err := r.cluster.HIncrBy(k, field, n).Result()
+		if err != nil {
+			return 0, err
+		}
+		return value, r.cluster.Expire(k, t).Err()
+	}
+	value, err := r.single.HIncrBy(k, field, n).Result()
+	if err != nil {
+		return 0, err
+	}
+	return value, r.single.Expire(k, t).Err()
+}
+
+// Keys iterate the keys matching k with SCAN, on every master in cluster mode,
+// so redis is never blocked by a KEYS on a large keyspace.
 func (r *Redis) Keys(k string) []string {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	if !r.clusterMode {
+		keys, err := scan(r.single, k)
+		if err != nil {
+			log.Errorf("redis scan %v err => %v", k, err)
+		}
+		return keys
+	}
+
+	var mu sync.Mutex
+	var keys []string
+	err := r.cluster.ForEachMaster(func(c *redis.Client) error {
+		shard, err := scan(c, k)
+		mu.Lock()
+		keys = append(keys, shard...)
+		mu.Unlock()
+		return err
+	})
+	if err != nil {
+		log.Errorf("redis scan %v err => %v", k, err)
+	}
+	return keys
+}
+
+func scan(c *redis.Client, match string) ([]string, error) {
+	var keys []string
+	// SCAN may return a key more than once
+	seen := make(map[string]bool)
+	var cursor uint64
+	for {
+		batch, next, err := c.Scan(cursor, match, scanCount).Result()
+		if err != nil {
+			return keys, err
+		}
+		for _, key := range batch {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+		if next == 0 {
+			return keys, nil
+		}
+		cursor = next
+	}
+}
+
+func (r *Redis) pipeline() redis.Pipeliner {
 	if r.clusterMode {
-		return r.cluster.Keys(k).Val()
+		return r.cluster.Pipeline()
 	}
-	return r.single.Keys(k).Val()
+	return r.single.Pipeline()
+}
+
+// MGet get the values of keys in one pipeline, "" for the missing keys.
+// Unlike MGET, the keys may live on different cluster slots.
+func (r *Redis) MGet(keys []string) []string {
+	values := make([]string, len(keys))
+	if len(keys) == 0 {
+		return values
+	}
+	pipe := r.pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(key)
+	}
+	if _, err := pipe.Exec(); err != nil && err != redis.Nil {
+		log.Errorf("redis pipeline get err => %v", err)
+	}
+	for i, cmd := range cmds {
+		values[i] = cmd.Val()
+	}
+	return values
+}
+
+// MSet set the key/values of kv with ttl t in one pipeline
+func (r *Redis) MSet(kv map[string]string, t time.Duration) error {
+	if len(kv) == 0 {
+		return nil
+	}
+	pipe := r.pipeline()
+	for k, v := range kv {
+		pipe.Set(k, v, t)
+	}
+	_, err := pipe.Exec()
+	return err
 }
 
 func (r *Redis) Del(k string) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	if r.clusterMode {
 		return r.cluster.Del(k).Err()
 	}
 	return r.single.Del(k).Err()
 }
 
-// Watch http://redisdoc.com/topic/notification.html
-func (r *Redis) Watch(ctx context.Context, key string) <-chan interface{} {
-	var pubsub *redis.PubSub
+// Watch send the changes of the keys matching key until ctx is done,
+// the subscription is renewed if the connection drops.
+// On a cluster every master is watched, as notifications are local to a node.
+// http://redisdoc.com/topic/notification.html
+func (r *Redis) Watch(ctx context.Context, key string) (<-chan Event, error) {
+	if err := r.checkNotify(); err != nil {
+		return nil, err
+	}
+
+	var clients []*redis.Client
 	if r.clusterMode {
-		pubsub = r.cluster.PSubscribe(fmt.Sprintf("__key*__:%s", key))
+		err := r.cluster.ForEachMaster(func(c *redis.Client) error {
+			clients = append(clients, c)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
 	} else {
-		pubsub = r.single.PSubscribe(fmt.Sprintf("__key*__:%s", key))
+		clients = append(clients, r.single)
 	}
 
-	res := make(chan interface{})
+	res := make(chan Event, watchBuffer)
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		wg.Add(1)
+		go func(c *redis.Client) {
+			defer wg.Done()
+			r.watch(ctx, c, key, res)
+		}(c)
+	}
+	go func() {
+		wg.Wait()
+		close(res)
+	}()
+
+	return res, nil
+}
+
+// watch subscribe to the keyspace notifications of c until ctx is done
+func (r *Redis) watch(ctx context.Context, c *redis.Client, key string, res chan<- Event) {
+	delay := connectRetryMin
+	for {
+		pubsub := c.PSubscribe("__keyspace@*__:" + key)
+		err := r.receive(ctx, pubsub, res)
+		pubsub.Close()
+		if err == nil {
+			return
+		}
+
+		log.Warnf("redis watch %v: %v, resubscribe in %v", key, err, delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > connectRetryMax {
+			delay = connectRetryMax
+		}
+	}
+}
+
+// receive forward the notifications of pubsub until ctx is done (nil) or the subscription fails
+func (r *Redis) receive(ctx context.Context, pubsub *redis.PubSub, res chan<- Event) error {
+	// the subscription is confirmed before anything else
+	if _, err := pubsub.Receive(); err != nil {
+		return err
+	}
+
+	msgs := make(chan *redis.Message)
+	errs := make(chan error, 1)
 	go func() {
 		for {
+			msg, err := pubsub.ReceiveMessage()
+			if err != nil {
+				errs <- err
+				return
+			}
 			select {
-			case msg := <-pubsub.Channel():
-				op := msg.Payload
-				log.Infof("key => %s, op => %s", key, op)
-				res <- op
+			case msgs <- msg:
 			case <-ctx.Done():
-				pubsub.Close()
-				close(res)
 				return
 			}
 		}
 	}()
 
-	return res
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case msg := <-msgs:
+			// channel = __keyspace@${db}__:${key}, payload = ${op}
+			i := strings.Index(msg.Channel, "__:")
+			if i < 0 {
+				continue
+			}
+			event := Event{
+				Key: msg.Channel[i+3:],
+				Op:  Op(msg.Payload),
+			}
+			if event.Op == OpSet {
+				event.Value = r.Get(event.Key).(string)
+			}
+			select {
+			case res <- event:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// checkNotify make sure the keyspace notifications are enabled, CONFIG SET
+// in NewRedis may have been refused.
+func (r *Redis) checkNotify() error {
+	var val []interface{}
+	var err error
+	if r.clusterMode {
+		val, err = r.cluster.ConfigGet("notify-keyspace-events").Result()
+	} else {
+		val, err = r.single.ConfigGet("notify-keyspace-events").Result()
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotifyUnavailable, err)
+	}
+	if len(val) < 2 {
+		return ErrNotifyUnavailable
+	}
+	if flags, _ := val[1].(string); !notifyEnabled(flags) {
+		return fmt.Errorf("%w: notify-keyspace-events = %q", ErrNotifyUnavailable, flags)
+	}
+	return nil
 }
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Store backends
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+)
+
+// Store is a key/value store with hashes, ttl and keyspace notifications,
+// implemented by Redis and Memory.
+type Store interface {
+	Set(k, v string, t time.Duration) error
+	// SetNX set k to v with ttl t only if k does not exist, return whether it was set
+	SetNX(k, v string, t time.Duration) (bool, error)
+	// Incr increment the integer value of k, which starts at 0
+	Incr(k string) (int64, error)
+	// Get return the string value of k, "" if k does not exist
+	Get(k string) interface{}
+	HSet(k, field string, value interface{}) error
+	HGet(k, field string) string
+	HGetAll(k string) map[string]string
+	HDel(k, field string) error
+	Expire(k string, t time.Duration) error
+	HSetTTL(k, field string, value interface{}, t time.Duration) error
+	// HIncrTTL increment the integer field of the hash k by n, which starts at 0,
+	// and set the ttl t of k
+	HIncrTTL(k, field string, n int64, t time.Duration) (int64, error)
+	// Keys return the keys matching the glob pattern k
+	Keys(k string) []string
+	// MGet return the values of keys, "" for the missing ones
+	MGet(keys []string) []string
+	// MSet set the key/values of kv with ttl t
+	MSet(kv map[string]string, t time.Duration) error
+	Del(k string) error
+	// Watch send the changes of the keys matching the glob pattern key until ctx is done,
+	// then close the channel
+	Watch(ctx context.Context, key string) (<-chan Event, error)
+	Close()
+}
+
+// globEscaper escape the special characters of the redis glob patterns
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// EscapeGlob return s matching itself only in the patterns of Keys and Watch,
+// for the ids sent by the clients
+func EscapeGlob(s string) string {
+	return globEscaper.Replace(s)
+}
+
+var (
+	_ Store = (*Redis)(nil)
+	_ Store = (*Memory)(nil)
+)
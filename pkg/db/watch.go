@@ -0,0 +1,50 @@
+package db
+
+import (
+	"errors"
+	"strings"
+)
+
+// Op a keyspace operation, as named by redis keyspace notifications
+type Op string
+
+// Keyspace operations, the other redis operations (hset, expire...) are passed as is
+const (
+	OpSet     Op = "set"
+	OpDel     Op = "del"
+	OpExpired Op = "expired"
+)
+
+// Event a change of a watched key
+type Event struct {
+	Key string
+	Op  Op
+	// Value the value of Key after a set, "" for the other operations
+	Value string
+}
+
+const (
+	// events buffered for a watcher
+	watchBuffer = 128
+)
+
+// ErrNotifyUnavailable keyspace notifications are disabled on the server and can not be
+// enabled by CONFIG SET, as on most managed redis services
+var ErrNotifyUnavailable = errors.New("redis keyspace notifications unavailable, set notify-keyspace-events to AKE on the server")
+
+// notifyEnabled return true if the notify-keyspace-events flags send the keyspace
+// notifications of the generic, string, hash and expired events
+func notifyEnabled(flags string) bool {
+	if !strings.Contains(flags, "K") {
+		return false
+	}
+	if strings.Contains(flags, "A") {
+		return true
+	}
+	for _, f := range []string{"g", "$", "h", "x"} {
+		if !strings.Contains(flags, f) {
+			return false
+		}
+	}
+	return true
+}
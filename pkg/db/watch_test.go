@@ -0,0 +1,21 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyEnabled(t *testing.T) {
+	for flags, enabled := range map[string]bool{
+		"":      false,
+		"AKE":   true,
+		"KA":    true,
+		"EA":    false,
+		"Kg$hx": true,
+		"Kg$x":  false,
+		"Ex":    false,
+	} {
+		assert.Equal(t, enabled, notifyEnabled(flags), flags)
+	}
+}
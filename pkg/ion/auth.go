@@ -0,0 +1,104 @@
+package ion
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
+)
+
+// keys of the credential carried in discovery.Node.RPC.Params
+const (
+	// paramOrigin the random id of the process sending the node info,
+	// tells a restarted or duplicated node from the live one
+	paramOrigin = "origin"
+	// paramTime the unix time the node info was signed at, a captured
+	// record is only accepted within NodeTimeWindow
+	paramTime = "ts"
+	// paramToken hmac-sha256 over the whole node info but the token itself
+	paramToken = "token"
+)
+
+// NodeTimeWindow how far the time of a signed node info may be from the
+// clock of the registry, keepalives are sent well within it
+const NodeTimeWindow = 30 * time.Second
+
+// errors returned by VerifyNode
+var (
+	ErrNodeUnsigned = errors.New("node info is not signed")
+	ErrNodeBadToken = errors.New("node info has a bad signature")
+	ErrNodeNoOrigin = errors.New("node info has no origin")
+	ErrNodeStale    = errors.New("node info is stale")
+)
+
+func newOrigin() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// NodeOrigin return the id of the process that sent node
+func NodeOrigin(node discovery.Node) string {
+	return node.RPC.Params[paramOrigin]
+}
+
+// NodeTime return the time node was signed at, zero if it has none
+func NodeTime(node discovery.Node) time.Time {
+	ts, err := strconv.ParseInt(node.RPC.Params[paramTime], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(ts, 0)
+}
+
+// SignNode return the credential of node for the cluster secret, it covers
+// the rpc address and every param, the load figures, origin and time included
+func SignNode(secret string, node discovery.Node) string {
+	fields := []string{node.DC, node.Service, node.NID, string(node.RPC.Protocol), node.RPC.Addr}
+	keys := make([]string, 0, len(node.RPC.Params))
+	for k := range node.RPC.Params {
+		if k != paramToken {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, k, node.RPC.Params[k])
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, s := range fields {
+		_, _ = mac.Write([]byte(s))
+		// separator, so fields can not be shifted into each other
+		_, _ = mac.Write([]byte{0})
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyNode check the credential of node against the cluster secret,
+// and that it was signed within NodeTimeWindow of now
+func VerifyNode(secret string, node discovery.Node) error {
+	token := node.RPC.Params[paramToken]
+	if token == "" {
+		return ErrNodeUnsigned
+	}
+	if NodeOrigin(node) == "" {
+		return ErrNodeNoOrigin
+	}
+	if !hmac.Equal([]byte(token), []byte(SignNode(secret, node))) {
+		return ErrNodeBadToken
+	}
+	signed := NodeTime(node)
+	if d := time.Since(signed); signed.IsZero() || d > NodeTimeWindow || d < -NodeTimeWindow {
+		return ErrNodeStale
+	}
+	return nil
+}
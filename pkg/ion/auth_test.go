@@ -0,0 +1,62 @@
+package ion
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
+	"github.com/pion/ion/pkg/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyNode(t *testing.T) {
+	n := NewNode("sfu-01")
+	n.SetSecret("s3cret")
+	n.self = discovery.Node{
+		DC:      "dc1",
+		Service: proto.ServiceSFU,
+		NID:     "sfu-01",
+		RPC:     discovery.RPC{Protocol: discovery.NGRPC},
+	}
+	node := n.record()
+	assert.NoError(t, VerifyNode("s3cret", node))
+	assert.Equal(t, ErrNodeBadToken, VerifyNode("other", node))
+
+	forged := node
+	forged.Service = proto.ServiceISLB
+	assert.Equal(t, ErrNodeBadToken, VerifyNode("s3cret", forged))
+
+	// the address and the params are signed too
+	forged = node
+	forged.RPC.Addr = "nats://evil:4222"
+	assert.Equal(t, ErrNodeBadToken, VerifyNode("s3cret", forged))
+	forged = node
+	forged.RPC.Params = make(map[string]string)
+	for k, v := range node.RPC.Params {
+		forged.RPC.Params[k] = v
+	}
+	forged.RPC.Params[paramPeers] = "99"
+	assert.Equal(t, ErrNodeBadToken, VerifyNode("s3cret", forged))
+	forged.RPC.Params[paramPeers] = node.RPC.Params[paramPeers]
+	assert.NoError(t, VerifyNode("s3cret", forged))
+	forged.RPC.Params["extra"] = "1"
+	assert.Equal(t, ErrNodeBadToken, VerifyNode("s3cret", forged))
+
+	// a captured record is only accepted within the time window
+	stale := node
+	stale.RPC.Params = make(map[string]string)
+	for k, v := range node.RPC.Params {
+		stale.RPC.Params[k] = v
+	}
+	stale.RPC.Params[paramTime] = strconv.FormatInt(time.Now().Add(-2*NodeTimeWindow).Unix(), 10)
+	stale.RPC.Params[paramToken] = SignNode("s3cret", stale)
+	assert.Equal(t, ErrNodeStale, VerifyNode("s3cret", stale))
+	delete(stale.RPC.Params, paramTime)
+	stale.RPC.Params[paramToken] = SignNode("s3cret", stale)
+	assert.Equal(t, ErrNodeStale, VerifyNode("s3cret", stale))
+
+	n.SetSecret("")
+	assert.Equal(t, ErrNodeUnsigned, VerifyNode("s3cret", n.record()))
+	assert.NotEqual(t, NodeOrigin(node), NodeOrigin(NewNode("sfu-01").record()))
+}
@@ -0,0 +1,125 @@
+package ion
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
+)
+
+// keys of the load figures carried in discovery.Node.RPC.Params
+const (
+	paramSessions  = "sessions"
+	paramPeers     = "peers"
+	paramBandwidth = "bandwidth"
+	paramDraining  = "draining"
+
+	paramMaxSessions  = "max_sessions"
+	paramMaxPeers     = "max_peers"
+	paramMaxBandwidth = "max_bandwidth"
+)
+
+// ErrClusterFull is returned by the islb when every node of a service is at its capacity.
+var ErrClusterFull = errors.New("cluster full")
+
+// Load is the load figures a node publishes with its discovery record.
+type Load struct {
+	// Sessions number of sessions hosted by the node
+	Sessions int
+	// Peers number of peers connected to the node
+	Peers int
+	// Bandwidth kbps received and sent by the peers, as measured
+	Bandwidth int
+}
+
+func (l Load) encode(params map[string]string) {
+	params[paramSessions] = strconv.Itoa(l.Sessions)
+	params[paramPeers] = strconv.Itoa(l.Peers)
+	params[paramBandwidth] = strconv.Itoa(l.Bandwidth)
+}
+
+// GetNodeLoad return the load figures published by node,
+// missing figures are reported as zero.
+func GetNodeLoad(node discovery.Node) Load {
+	params := node.RPC.Params
+	return Load{
+		Sessions:  atoi(params[paramSessions]),
+		Peers:     atoi(params[paramPeers]),
+		Bandwidth: atoi(params[paramBandwidth]),
+	}
+}
+
+// Capacity is the limits a node advertises with its discovery record,
+// zero means no limit.
+type Capacity struct {
+	// MaxSessions number of sessions the node can host
+	MaxSessions int `mapstructure:"max_sessions"`
+	// MaxPeers number of peers the node can connect
+	MaxPeers int `mapstructure:"max_peers"`
+	// MaxBandwidth in kbps
+	MaxBandwidth int `mapstructure:"max_bandwidth"`
+}
+
+func (c Capacity) encode(params map[string]string) {
+	if c.MaxSessions > 0 {
+		params[paramMaxSessions] = strconv.Itoa(c.MaxSessions)
+	}
+	if c.MaxPeers > 0 {
+		params[paramMaxPeers] = strconv.Itoa(c.MaxPeers)
+	}
+	if c.MaxBandwidth > 0 {
+		params[paramMaxBandwidth] = strconv.Itoa(c.MaxBandwidth)
+	}
+}
+
+// Admits return true if a node with load l can take one more peer,
+// in a new session if newSession is set.
+func (c Capacity) Admits(l Load, newSession bool) bool {
+	if newSession && c.MaxSessions > 0 && l.Sessions >= c.MaxSessions {
+		return false
+	}
+	if c.MaxPeers > 0 && l.Peers >= c.MaxPeers {
+		return false
+	}
+	if c.MaxBandwidth > 0 && l.Bandwidth >= c.MaxBandwidth {
+		return false
+	}
+	return true
+}
+
+// GetNodeCapacity return the limits published by node,
+// missing limits are reported as zero.
+func GetNodeCapacity(node discovery.Node) Capacity {
+	params := node.RPC.Params
+	return Capacity{
+		MaxSessions:  atoi(params[paramMaxSessions]),
+		MaxPeers:     atoi(params[paramMaxPeers]),
+		MaxBandwidth: atoi(params[paramMaxBandwidth]),
+	}
+}
+
+// CanAdmit return true if node has room for one more peer,
+// in a new session if newSession is set.
+func CanAdmit(node discovery.Node, newSession bool) bool {
+	return GetNodeCapacity(node).Admits(GetNodeLoad(node), newSession)
+}
+
+// IsClusterFull return true if err is ErrClusterFull, also once
+// it went through nats and only its message is left.
+func IsClusterFull(err error) bool {
+	return err != nil && strings.Contains(err.Error(), ErrClusterFull.Error())
+}
+
+// IsDraining return true if node is draining and should not get new sessions.
+func IsDraining(node discovery.Node) bool {
+	return node.RPC.Params[paramDraining] == "true"
+}
+
+func atoi(s string) int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return i
+}
@@ -3,13 +3,17 @@ package ion
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	ndc "github.com/cloudwebrtc/nats-discovery/pkg/client"
 	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
 	nrpc "github.com/cloudwebrtc/nats-grpc/pkg/rpc"
 	"github.com/nats-io/nats.go"
 	log "github.com/pion/ion-log"
+	"github.com/pion/ion/pkg/proto"
 	"github.com/pion/ion/pkg/util"
 	"google.golang.org/grpc"
 )
@@ -30,18 +34,90 @@ type Node struct {
 	neighborNodes map[string]discovery.Node
 
 	cliLock sync.RWMutex
-	clis    map[string]*nrpc.Client
+	// rpc clients pooled by service/nid
+	clis map[string]*pooledClient
+
+	// selector picks a node when more than one can serve a request
+	selector Selector
+
+	// onNodeDown is called when a neighbor node goes down
+	onNodeDown func(node discovery.Node)
+
+	selfLock sync.RWMutex
+	// node info uploaded by KeepAlive
+	self     discovery.Node
+	load     Load
+	capacity Capacity
+	draining bool
+	// origin tells this process from another one using the same NID
+	origin string
+	// secret signs the node info, see SignNode
+	secret string
+
+	sendLock sync.Mutex
+	// registered is set by KeepAlive, deregistered by Drain
+	registered   bool
+	deregistered bool
+
+	// number of streams in progress, see StreamStarted
+	streams int32
+
+	done   chan struct{}
+	closed util.AtomicBool
 }
 
+const (
+	// keepAliveCycle must stay well below discovery.DefaultExpire
+	keepAliveCycle = 2 * time.Second
+	drainCheck     = 500 * time.Millisecond
+
+	// DefaultDrainTimeout how long a node waits for its streams to end when draining
+	DefaultDrainTimeout = 30 * time.Second
+)
+
 //NewNode .
 func NewNode(nid string) Node {
 	return Node{
 		NID:           nid,
 		neighborNodes: make(map[string]discovery.Node),
-		clis:          make(map[string]*nrpc.Client),
+		clis:          make(map[string]*pooledClient),
+		selector:      NewSelector(SelectorRoundRobin),
+		origin:        newOrigin(),
+		done:          make(chan struct{}),
 	}
 }
 
+//SetSelector set the strategy used to pick a node for new sessions.
+func (n *Node) SetSelector(s Selector) {
+	n.selector = s
+}
+
+//Selector return the strategy used to pick a node for new sessions.
+func (n *Node) Selector() Selector {
+	return n.selector
+}
+
+//SetCapacity set the limits uploaded with the node info,
+//the islb gives no new peers to a node at its limits.
+func (n *Node) SetCapacity(c Capacity) {
+	n.selfLock.Lock()
+	defer n.selfLock.Unlock()
+	n.capacity = c
+}
+
+//Origin return the random id of this process, see NodeOrigin.
+func (n *Node) Origin() string {
+	return n.origin
+}
+
+//SetSecret set the cluster secret signing the node info uploaded by KeepAlive,
+//the islb rejects unsigned nodes when it has a secret.
+func (n *Node) SetSecret(secret string) {
+	n.selfLock.Lock()
+	defer n.selfLock.Unlock()
+	n.secret = secret
+}
+
 //Start .
 func (n *Node) Start(natURL string) error {
 	var err error
@@ -58,6 +134,7 @@ func (n *Node) Start(natURL string) error {
 		return err
 	}
 	n.nrpc = nrpc.NewServer(n.nc, n.NID)
+	go n.evictIdleClients()
 	return nil
 }
 
@@ -67,19 +144,187 @@ func (n *Node) NatsConn() *nats.Conn {
 }
 
 //KeepAlive Upload your node info to registry.
+//The node info is sent again every keepAliveCycle together with the
+//latest load figures, until the node is closed.
 func (n *Node) KeepAlive(node discovery.Node) error {
-	return n.ndc.KeepAlive(node)
+	n.selfLock.Lock()
+	n.self = node
+	n.selfLock.Unlock()
+
+	n.sendLock.Lock()
+	n.registered = true
+	n.sendLock.Unlock()
+
+	// a rejected node keeps trying, the registry refuses a NID
+	// until the record of its previous process expires.
+	err := n.sendRecord(discovery.Save)
+	if err != nil {
+		log.Errorf("keepalive: send save error %v", err)
+	}
+
+	t := time.NewTicker(keepAliveCycle)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			err := n.sendRecord(discovery.Update)
+			if err != nil {
+				log.Errorf("keepalive: send update error %v", err)
+			}
+		case <-n.done:
+			return nil
+		}
+	}
+}
+
+//UpdateLoad set the load figures uploaded with the next keepalive.
+func (n *Node) UpdateLoad(load Load) {
+	n.selfLock.Lock()
+	defer n.selfLock.Unlock()
+	n.load = load
+}
+
+// record return a copy of the node info with the current load figures
+func (n *Node) record() discovery.Node {
+	n.selfLock.RLock()
+	defer n.selfLock.RUnlock()
+	node := n.self
+	params := make(map[string]string, len(n.self.RPC.Params)+5)
+	for k, v := range n.self.RPC.Params {
+		params[k] = v
+	}
+	n.load.encode(params)
+	n.capacity.encode(params)
+	if n.draining {
+		params[paramDraining] = "true"
+	}
+	params[paramOrigin] = n.origin
+	params[paramTime] = strconv.FormatInt(time.Now().Unix(), 10)
+	node.RPC.Params = params
+	if n.secret != "" {
+		params[paramToken] = SignNode(n.secret, node)
+	}
+	return node
+}
+
+// sendRecord upload the node info, nothing is sent once the node is deregistered.
+func (n *Node) sendRecord(action discovery.Action) error {
+	n.sendLock.Lock()
+	defer n.sendLock.Unlock()
+	if !n.registered || n.deregistered || n.ndc == nil {
+		return nil
+	}
+	if action == discovery.Delete {
+		n.deregistered = true
+	}
+	return n.ndc.SendAction(n.record(), action)
 }
 
+//StreamStarted count a stream served by the node, Drain waits for it to end.
+func (n *Node) StreamStarted() {
+	atomic.AddInt32(&n.streams, 1)
+}
+
+//StreamEnded must be called once for each StreamStarted.
+func (n *Node) StreamEnded() {
+	atomic.AddInt32(&n.streams, -1)
+}
+
+//Draining return true once Drain is called.
+func (n *Node) Draining() bool {
+	n.selfLock.RLock()
+	defer n.selfLock.RUnlock()
+	return n.draining
+}
+
+//Drain mark the node as draining so no new session is routed to it, wait for
+//the streams in progress to end or ctx to be done, then deregister and close the node.
+func (n *Node) Drain(ctx context.Context) error {
+	n.selfLock.Lock()
+	n.draining = true
+	n.selfLock.Unlock()
+
+	// don't wait for the next keepalive to tell the registry.
+	if err := n.sendRecord(discovery.Update); err != nil {
+		log.Errorf("drain: send update error %v", err)
+	}
+
+	var err error
+	t := time.NewTicker(drainCheck)
+	defer t.Stop()
+	for atomic.LoadInt32(&n.streams) > 0 && err == nil {
+		log.Debugf("drain: waiting for %v streams", atomic.LoadInt32(&n.streams))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			err = ctx.Err()
+			log.Warnf("drain: %v streams left, %v", atomic.LoadInt32(&n.streams), err)
+		}
+	}
+
+	if err := n.sendRecord(discovery.Delete); err != nil {
+		log.Errorf("drain: send delete error %v", err)
+	}
+	n.Close()
+	return err
+}
+
+//NewNatsRPCClient return a client to a node of service.
+//peerNID selects the node, "*" lets the selector pick one among the neighbor
+//nodes (or the nodes returned by discovery), unless parameters carry a "nid".
+//The sfu and rtc nodes are always resolved by discovery, so islb keeps a
+//session on its sfu and admits new sessions by capacity.
+//A "dc" parameter restricts the nodes to that dc, otherwise the nodes of the
+//dc of this node are preferred.
+//Clients are shared per service/nid, call Release when done with it.
 func (n *Node) NewNatsRPCClient(service, peerNID string, parameters map[string]interface{}) (*nrpc.Client, error) {
 	var cli *nrpc.Client = nil
-	selfNID := n.NID
-	for id, node := range n.neighborNodes {
-		if node.Service == service && (id == peerNID || peerNID == "*") {
-			cli = nrpc.NewClient(n.nc, id, selfNID)
+	sid, _ := parameters["sid"].(string)
+	routed := peerNID == "*" && (service == proto.ServiceSFU || service == proto.ServiceRTC)
+	if nid, ok := parameters["nid"].(string); ok && nid != "" && peerNID == "*" {
+		peerNID = nid
+	}
+
+	n.selfLock.RLock()
+	callerDC := n.self.DC
+	n.selfLock.RUnlock()
+	dc, _ := parameters["dc"].(string)
+	if dc == "" && callerDC != "" {
+		if _, ok := parameters["caller_dc"]; !ok {
+			params := make(map[string]interface{}, len(parameters)+1)
+			for k, v := range parameters {
+				params[k] = v
+			}
+			params["caller_dc"] = callerDC
+			parameters = params
 		}
 	}
 
+	var candidates, local []discovery.Node
+	if !routed {
+		n.nodeLock.RLock()
+		for id, node := range n.neighborNodes {
+			if dc != "" && node.DC != dc {
+				continue
+			}
+			if node.Service == service && (id == peerNID || (peerNID == "*" && !IsDraining(node))) {
+				candidates = append(candidates, node)
+				if node.DC == callerDC {
+					local = append(local, node)
+				}
+			}
+		}
+		n.nodeLock.RUnlock()
+	}
+	if len(local) > 0 {
+		candidates = local
+	}
+
+	if len(candidates) > 0 {
+		node := n.selector.Select(sid, candidates)
+		cli = n.acquireClient(service, node.NID)
+	}
+
 	if cli == nil {
 		resp, err := n.ndc.Get(service, parameters)
 		if err != nil {
@@ -92,12 +337,10 @@ func (n *Node) NewNatsRPCClient(service, peerNID string, parameters map[string]i
 			return nil, err
 		}
 
-		cli = nrpc.NewClient(n.nc, resp.Nodes[0].NID, selfNID)
+		node := n.selector.Select(sid, resp.Nodes)
+		cli = n.acquireClient(service, node.NID)
 	}
 
-	n.cliLock.Lock()
-	defer n.cliLock.Unlock()
-	n.clis[util.RandomString(12)] = cli
 	return cli, nil
 }
 
@@ -116,6 +359,11 @@ func (n *Node) Watch(service string) error {
 	return n.ndc.Watch(context.Background(), service, n.handleNeighborNodes)
 }
 
+//OnNodeDown set the handler called when a neighbor node goes down, must be set before Watch
+func (n *Node) OnNodeDown(handler func(node discovery.Node)) {
+	n.onNodeDown = handler
+}
+
 // GetNeighborNodes get neighbor nodes.
 func (n *Node) GetNeighborNodes() map[string]discovery.Node {
 	n.nodeLock.Lock()
@@ -128,11 +376,13 @@ func (n *Node) handleNeighborNodes(state discovery.NodeState, node *discovery.No
 	id := node.NID
 	service := node.Service
 	if state == discovery.NodeUp {
-		log.Infof("Service up: "+service+" node id => [%v], rpc => %v", id, node.RPC.Protocol)
-		if _, found := n.neighborNodes[id]; !found {
-			n.nodeLock.Lock()
-			n.neighborNodes[id] = *node
-			n.nodeLock.Unlock()
+		n.nodeLock.Lock()
+		_, found := n.neighborNodes[id]
+		// always keep the latest record, it carries the load figures
+		n.neighborNodes[id] = *node
+		n.nodeLock.Unlock()
+		if !found {
+			log.Infof("Service up: "+service+" node id => [%v], rpc => %v", id, node.RPC.Protocol)
 		}
 	} else if state == discovery.NodeDown {
 		log.Infof("Service down: "+service+" node id => [%v]", id)
@@ -146,11 +396,15 @@ func (n *Node) handleNeighborNodes(state discovery.NodeState, node *discovery.No
 			log.Errorf("nrpc.CloseStream: err %v", err)
 		}
 
+		if n.onNodeDown != nil {
+			n.onNodeDown(*node)
+		}
+
 		n.cliLock.Lock()
 		defer n.cliLock.Unlock()
-		for cid, cli := range n.clis {
-			if cli.CloseStream(id) {
-				delete(n.clis, cid)
+		for key, pc := range n.clis {
+			if pc.cli.CloseStream(id) {
+				delete(n.clis, key)
 			}
 		}
 	}
@@ -163,6 +417,18 @@ func (n *Node) ServiceRegistrar() grpc.ServiceRegistrar {
 
 //Close .
 func (n *Node) Close() {
+	if !n.closed.Set(true) {
+		return
+	}
+	if n.done != nil {
+		close(n.done)
+	}
+	n.cliLock.Lock()
+	for key, pc := range n.clis {
+		pc.cli.Close()
+		delete(n.clis, key)
+	}
+	n.cliLock.Unlock()
 	if n.nrpc != nil {
 		n.nrpc.Stop()
 	}
@@ -0,0 +1,93 @@
+package ion
+
+import (
+	"time"
+
+	nrpc "github.com/cloudwebrtc/nats-grpc/pkg/rpc"
+	log "github.com/pion/ion-log"
+)
+
+const (
+	// unreferenced clients are closed after clientIdleTimeout
+	clientIdleTimeout = time.Minute
+	clientIdleCheck   = 10 * time.Second
+)
+
+// pooledClient a nats rpc client shared by every caller of the same (service, nid)
+type pooledClient struct {
+	cli     *nrpc.Client
+	service string
+	nid     string
+	refs    int
+	// time the last reference was released
+	idleAt time.Time
+}
+
+func poolKey(service, nid string) string {
+	return service + "/" + nid
+}
+
+// acquireClient return the pooled client of (service, nid), create it if
+// needed, and take a reference on it.
+func (n *Node) acquireClient(service, nid string) *nrpc.Client {
+	n.cliLock.Lock()
+	defer n.cliLock.Unlock()
+	key := poolKey(service, nid)
+	pc, found := n.clis[key]
+	if !found {
+		pc = &pooledClient{
+			cli:     nrpc.NewClient(n.nc, nid, n.NID),
+			service: service,
+			nid:     nid,
+		}
+		n.clis[key] = pc
+	}
+	pc.refs++
+	return pc.cli
+}
+
+//Release drop a reference taken by NewNatsRPCClient, the client is closed
+//once it has stayed unreferenced for clientIdleTimeout.
+func (n *Node) Release(cli *nrpc.Client) {
+	n.cliLock.Lock()
+	defer n.cliLock.Unlock()
+	for _, pc := range n.clis {
+		if pc.cli != cli {
+			continue
+		}
+		if pc.refs > 0 {
+			pc.refs--
+		}
+		if pc.refs == 0 {
+			pc.idleAt = time.Now()
+		}
+		return
+	}
+}
+
+// evictIdleClients close the clients nobody uses anymore, until the node is closed.
+func (n *Node) evictIdleClients() {
+	t := time.NewTicker(clientIdleCheck)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-n.done:
+			return
+		}
+		n.evictIdle(time.Now())
+	}
+}
+
+// evictIdle close the clients unreferenced for clientIdleTimeout at now
+func (n *Node) evictIdle(now time.Time) {
+	n.cliLock.Lock()
+	defer n.cliLock.Unlock()
+	for key, pc := range n.clis {
+		if pc.refs == 0 && now.Sub(pc.idleAt) > clientIdleTimeout {
+			log.Debugf("close idle client %v", key)
+			pc.cli.Close()
+			delete(n.clis, key)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/ion/pkg/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolRefs(t *testing.T) {
+	n := NewNode("pool-01")
+	n.nc = nc
+
+	cli := n.acquireClient(proto.ServiceSFU, "sfu-01")
+	assert.Same(t, cli, n.acquireClient(proto.ServiceSFU, "sfu-01"))
+	assert.NotSame(t, cli, n.acquireClient(proto.ServiceSFU, "sfu-02"))
+	assert.NotSame(t, cli, n.acquireClient(proto.ServiceISLB, "sfu-01"))
+	pc := n.clis[poolKey(proto.ServiceSFU, "sfu-01")]
+	assert.Equal(t, 2, pc.refs)
+
+	n.Release(cli)
+	assert.Equal(t, 1, pc.refs)
+	assert.True(t, pc.idleAt.IsZero())
+	n.Release(cli)
+	assert.Equal(t, 0, pc.refs)
+	assert.False(t, pc.idleAt.IsZero())
+
+	// released more than acquired
+	n.Release(cli)
+	assert.Equal(t, 0, pc.refs)
+}
+
+func TestPoolEvict(t *testing.T) {
+	n := NewNode("pool-01")
+	n.nc = nc
+
+	idle := n.acquireClient(proto.ServiceSFU, "sfu-01")
+	used := n.acquireClient(proto.ServiceSFU, "sfu-02")
+	revived := n.acquireClient(proto.ServiceSFU, "sfu-03")
+	n.Release(idle)
+	n.Release(revived)
+	assert.Same(t, revived, n.acquireClient(proto.ServiceSFU, "sfu-03"))
+
+	now := time.Now()
+	n.evictIdle(now)
+	assert.Len(t, n.clis, 3)
+
+	n.evictIdle(now.Add(clientIdleTimeout + time.Second))
+	assert.Len(t, n.clis, 2)
+	assert.Nil(t, n.clis[poolKey(proto.ServiceSFU, "sfu-01")])
+	assert.Same(t, used, n.clis[poolKey(proto.ServiceSFU, "sfu-02")].cli)
+
+	// a new client once the idle one is closed
+	assert.NotSame(t, idle, n.acquireClient(proto.ServiceSFU, "sfu-01"))
+}
@@ -0,0 +1,142 @@
+package ion
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
+)
+
+// Selector names used in node configs.
+const (
+	SelectorRoundRobin     = "roundrobin"
+	SelectorLeastSessions  = "leastsessions"
+	SelectorLeastBandwidth = "leastbandwidth"
+	SelectorHash           = "hash"
+)
+
+// virtual nodes per node on the consistent hash ring
+const hashReplicas = 64
+
+// Selector picks the node a session should be placed on.
+// nodes is never empty.
+type Selector interface {
+	Select(sid string, nodes []discovery.Node) discovery.Node
+}
+
+// NewSelector create a selector by name, defaults to round-robin.
+func NewSelector(name string) Selector {
+	switch name {
+	case SelectorLeastSessions:
+		return &leastSessions{}
+	case SelectorLeastBandwidth:
+		return &leastBandwidth{}
+	case SelectorHash:
+		return &consistentHash{}
+	default:
+		return &roundRobin{}
+	}
+}
+
+// sortByNID sort a copy of nodes, so every strategy sees a stable order
+// whatever map it was collected from.
+func sortByNID(nodes []discovery.Node) []discovery.Node {
+	sorted := make([]discovery.Node, len(nodes))
+	copy(sorted, nodes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].NID < sorted[j].NID
+	})
+	return sorted
+}
+
+type roundRobin struct {
+	next uint32
+}
+
+func (r *roundRobin) Select(sid string, nodes []discovery.Node) discovery.Node {
+	sorted := sortByNID(nodes)
+	i := atomic.AddUint32(&r.next, 1) - 1
+	return sorted[int(i%uint32(len(sorted)))]
+}
+
+type leastSessions struct{}
+
+func (l *leastSessions) Select(sid string, nodes []discovery.Node) discovery.Node {
+	sorted := sortByNID(nodes)
+	best, bestLoad := sorted[0], GetNodeLoad(sorted[0])
+	for _, node := range sorted[1:] {
+		load := GetNodeLoad(node)
+		if load.Sessions < bestLoad.Sessions ||
+			(load.Sessions == bestLoad.Sessions && load.Peers < bestLoad.Peers) {
+			best, bestLoad = node, load
+		}
+	}
+	return best
+}
+
+type leastBandwidth struct{}
+
+func (l *leastBandwidth) Select(sid string, nodes []discovery.Node) discovery.Node {
+	sorted := sortByNID(nodes)
+	best, bestLoad := sorted[0], GetNodeLoad(sorted[0])
+	for _, node := range sorted[1:] {
+		load := GetNodeLoad(node)
+		if load.Bandwidth < bestLoad.Bandwidth ||
+			(load.Bandwidth == bestLoad.Bandwidth && load.Sessions < bestLoad.Sessions) {
+			best, bestLoad = node, load
+		}
+	}
+	return best
+}
+
+// consistentHash maps a sid onto a ring of virtual nodes, so a session keeps
+// landing on the same node while the node set is stable, and only the sessions
+// of a departed node move when it goes away.
+type consistentHash struct {
+	fallback roundRobin
+}
+
+func (c *consistentHash) Select(sid string, nodes []discovery.Node) discovery.Node {
+	if sid == "" {
+		return c.fallback.Select(sid, nodes)
+	}
+
+	type point struct {
+		hash uint32
+		node int
+	}
+	ring := make([]point, 0, len(nodes)*hashReplicas)
+	for i, node := range nodes {
+		for r := 0; r < hashReplicas; r++ {
+			ring = append(ring, point{hash: hash32(node.NID + "#" + strconv.Itoa(r)), node: i})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool {
+		return ring[i].hash < ring[j].hash
+	})
+
+	h := hash32(sid)
+	i := sort.Search(len(ring), func(i int) bool {
+		return ring[i].hash >= h
+	})
+	if i == len(ring) {
+		i = 0
+	}
+	return nodes[ring[i].node]
+}
+
+// hash32 fnv-1a followed by the murmur3 finalizer, plain fnv spreads
+// short keys like "sfu-01#1" poorly over the ring.
+func hash32(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	x := h.Sum32()
+	x ^= x >> 16
+	x *= 0x85ebca6b
+	x ^= x >> 13
+	x *= 0xc2b2ae35
+	x ^= x >> 16
+	return x
+}
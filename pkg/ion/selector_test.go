@@ -0,0 +1,84 @@
+package ion
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
+	"github.com/pion/ion/pkg/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLoadedNode(nid string, load Load) discovery.Node {
+	params := make(map[string]string)
+	load.encode(params)
+	return discovery.Node{
+		DC:      "dc1",
+		Service: proto.ServiceSFU,
+		NID:     nid,
+		RPC: discovery.RPC{
+			Protocol: discovery.NGRPC,
+			Params:   params,
+		},
+	}
+}
+
+func TestSelector(t *testing.T) {
+	nodes := []discovery.Node{
+		newLoadedNode("sfu-03", Load{Sessions: 2, Peers: 8, Bandwidth: 500}),
+		newLoadedNode("sfu-01", Load{Sessions: 1, Peers: 9, Bandwidth: 3000}),
+		newLoadedNode("sfu-02", Load{Sessions: 1, Peers: 2, Bandwidth: 1000}),
+	}
+
+	assert.Equal(t, "sfu-02", NewSelector(SelectorLeastSessions).Select("", nodes).NID)
+	assert.Equal(t, "sfu-03", NewSelector(SelectorLeastBandwidth).Select("", nodes).NID)
+
+	rr := NewSelector(SelectorRoundRobin)
+	var picked []string
+	for i := 0; i < 4; i++ {
+		picked = append(picked, rr.Select("", nodes).NID)
+	}
+	assert.Equal(t, []string{"sfu-01", "sfu-02", "sfu-03", "sfu-01"}, picked)
+
+	hash := NewSelector(SelectorHash)
+	first := hash.Select("room1", nodes).NID
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, hash.Select("room1", nodes).NID)
+	}
+
+	// only the sessions of a removed node move.
+	var rest []discovery.Node
+	for _, node := range nodes {
+		if node.NID != first {
+			rest = append(rest, node)
+		}
+	}
+	for _, sid := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		if nid := hash.Select(sid, nodes).NID; nid != first {
+			assert.Equal(t, nid, hash.Select(sid, rest).NID)
+		}
+	}
+}
+
+func TestGetNodeLoad(t *testing.T) {
+	load := Load{Sessions: 3, Peers: 12, Bandwidth: 4500}
+	assert.Equal(t, load, GetNodeLoad(newLoadedNode("sfu-01", load)))
+	assert.Equal(t, Load{}, GetNodeLoad(discovery.Node{NID: "sfu-02"}))
+}
+
+func TestCanAdmit(t *testing.T) {
+	node := newLoadedNode("sfu-01", Load{Sessions: 2, Peers: 9, Bandwidth: 900})
+	assert.True(t, CanAdmit(node, true))
+
+	Capacity{MaxSessions: 2}.encode(node.RPC.Params)
+	assert.Equal(t, Capacity{MaxSessions: 2}, GetNodeCapacity(node))
+	assert.False(t, CanAdmit(node, true))
+	assert.True(t, CanAdmit(node, false))
+
+	Capacity{MaxSessions: 2, MaxPeers: 9}.encode(node.RPC.Params)
+	assert.False(t, CanAdmit(node, false))
+
+	assert.False(t, Capacity{MaxBandwidth: 900}.Admits(GetNodeLoad(node), false))
+	assert.True(t, IsClusterFull(fmt.Errorf("nats: %v", ErrClusterFull)))
+	assert.False(t, IsClusterFull(nil))
+}
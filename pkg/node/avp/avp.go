@@ -1,6 +1,7 @@
 package avp
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -35,6 +36,8 @@ type elementConf struct {
 
 type nodeConf struct {
 	NID string `mapstructure:"nid"`
+	// Secret signs the node info, shared by every node of the cluster
+	Secret string `mapstructure:"secret"`
 }
 
 // Config for avp node
@@ -76,6 +79,7 @@ func (a *AVP) Start(conf Config) error {
 		a.Close()
 		return err
 	}
+	a.Node.SetSecret(conf.Node.Secret)
 
 	node := discovery.Node{
 		DC:      conf.Global.Dc,
@@ -110,7 +114,7 @@ func (a *AVP) Start(conf Config) error {
 		}
 	}
 
-	a.s = newAVPServer(conf.Config, elems)
+	a.s = newAVPServer(a, conf.Config, elems)
 	pb.RegisterAVPServer(a.Node.ServiceRegistrar(), a.s)
 
 	//Watch ALL nodes.
@@ -124,6 +128,13 @@ func (a *AVP) Start(conf Config) error {
 	return nil
 }
 
+// Drain stop taking new processes and close the node once the running ones end or ctx is done
+func (a *AVP) Drain(ctx context.Context) error {
+	err := a.Node.Drain(ctx)
+	a.Close()
+	return err
+}
+
 // Close all
 func (a *AVP) Close() {
 	a.Node.Close()
@@ -62,16 +62,21 @@ func (a *AVPProcesser) Process(ctx context.Context, addr, pid, sid, tid, eid str
 type avpServer struct {
 	pb.UnimplementedAVPServer
 	avp *AVPProcesser
+	an  *AVP
 }
 
-func newAVPServer(conf avp.Config, elems map[string]avp.ElementFun) *avpServer {
+func newAVPServer(an *AVP, conf avp.Config, elems map[string]avp.ElementFun) *avpServer {
 	return &avpServer{
 		avp: NewAVPProcesser(conf, elems),
+		an:  an,
 	}
 }
 
 // Signal handler for avp server
 func (s *avpServer) Signal(stream pb.AVP_SignalServer) error {
+	s.an.StreamStarted()
+	defer s.an.StreamEnded()
+
 	for {
 		in, err := stream.Recv()
 
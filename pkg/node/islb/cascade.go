@@ -0,0 +1,123 @@
+package islb
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/pion/ion-log"
+	"github.com/pion/ion/pkg/db"
+	"github.com/pion/ion/pkg/proto"
+	ion "github.com/pion/ion/proto/ion"
+	islb "github.com/pion/ion/proto/islb"
+	sfu "github.com/pion/ion/proto/sfu"
+	"github.com/square/go-jose/v3/json"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// cascadeSession tell every sfu of session sid to relay its publishers to the
+// others, then announce the streams of the session to the peers of edge.
+func (s *islbServer) cascadeSession(sid, edge string, nids []string) error {
+	if err := s.syncCascade(sid, nids); err != nil {
+		return err
+	}
+
+	// key = dc/nid/sid/uid
+	keys := s.store.Keys(s.conf.Global.Dc + "/*/" + db.EscapeGlob(sid) + "/*")
+	for i, value := range s.store.MGet(keys) {
+		strs := strings.Split(keys[i], "/")
+		if len(strs) < 4 || value == "" || strs[1] == edge {
+			continue
+		}
+		var streams []*ion.Stream
+		if err := json.Unmarshal([]byte(value), &streams); err != nil {
+			log.Errorf("json.Unmarshal %v err => %v", keys[i], err)
+			continue
+		}
+		s.publishEvent(relayedStreamEvent(&ion.StreamEvent{
+			State:   ion.StreamEvent_ADD,
+			Nid:     strs[1],
+			Sid:     strs[2],
+			Uid:     strs[3],
+			Streams: streams,
+		}, edge))
+	}
+	return nil
+}
+
+// syncCascade send the sfus of session sid to each of them. A sfu without
+// peers of the session answers NotFound, an edge keeps the cascade for its
+// peers to come, and the host of a session gone has nothing to relay.
+func (s *islbServer) syncCascade(sid string, nids []string) error {
+	for _, nid := range nids {
+		cli, err := s.islb.NewNatsRPCClient(proto.ServiceSFU, nid, map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		_, err = sfu.NewSFUClient(cli).Cascade(context.Background(), &sfu.CascadeRequest{
+			Sid:  sid,
+			Nids: nids,
+		})
+		s.islb.Release(cli)
+		if err != nil && status.Code(err) != codes.NotFound {
+			log.Errorf("sfu.Cascade(%v) on %v err => %v", sid, nid, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// uncascadeSession tell the sfus left in session sid that the edge is gone,
+// so that they stop counting it and cascade to it again when needed.
+func (s *islbServer) uncascadeSession(dc, sid, edge string) {
+	info, found := getSession(s.store, dc, sid)
+	if !found {
+		return
+	}
+	log.Infof("islb: session %v no longer cascaded to %v", sid, edge)
+	if err := s.syncCascade(sid, info.nids()); err != nil {
+		log.Errorf("islb: uncascade session %v from %v failed: %v", sid, edge, err)
+	}
+}
+
+// relayedEvents return the copies of a stream event for the other sfus of its
+// session, when the session is cascaded. The copies are not saved to the store,
+// the streams are only kept under the sfu they are published on.
+func (s *islbServer) relayedEvents(event *islb.ISLBEvent) []*islb.ISLBEvent {
+	payload, ok := event.Payload.(*islb.ISLBEvent_Stream)
+	if !ok {
+		return nil
+	}
+	stream := payload.Stream
+	if stream.Origin != "" && stream.Origin != stream.Nid {
+		return nil
+	}
+	info, found := getSession(s.store, s.conf.Global.Dc, stream.Sid)
+	if !found || len(info.Edges) == 0 {
+		return nil
+	}
+
+	var events []*islb.ISLBEvent
+	for _, nid := range info.nids() {
+		if nid != stream.Nid {
+			events = append(events, relayedStreamEvent(stream, nid))
+		}
+	}
+	return events
+}
+
+// relayedStreamEvent return the event of stream as relayed to the sfu nid
+func relayedStreamEvent(stream *ion.StreamEvent, nid string) *islb.ISLBEvent {
+	return &islb.ISLBEvent{
+		Payload: &islb.ISLBEvent_Stream{
+			Stream: &ion.StreamEvent{
+				State:   stream.State,
+				Nid:     nid,
+				Sid:     stream.Sid,
+				Uid:     stream.Uid,
+				Streams: stream.Streams,
+				Origin:  stream.Nid,
+			},
+		},
+	}
+}
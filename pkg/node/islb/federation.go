@@ -0,0 +1,204 @@
+package islb
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	log "github.com/pion/ion-log"
+	islb "github.com/pion/ion/proto/islb"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	// federationSubject carries the stream and session events between the islbs,
+	// nats gateways and leaf nodes forward it to the other clusters like any subject
+	federationSubject = "ion.islb.federation"
+	// interval of the heartbeats telling the other islbs this one is alive
+	federationHeartbeat = 5 * time.Second
+	// an origin not heard of for this long is gone, the records of its dc
+	// are purged unless another islb of the dc is alive
+	federationOriginTTL = 3 * federationHeartbeat
+)
+
+// federatedEvent an event posted to an islb, forwarded to the other islbs
+type federatedEvent struct {
+	// DC of the islb the event was posted to, its store keys the event under it
+	DC string `json:"dc"`
+	// Origin the islb process that forwarded the event
+	Origin string `json:"origin"`
+	// Seq numbers the events of Origin, to drop the ones delivered twice
+	Seq uint64 `json:"seq"`
+	// Event the protobuf encoded islb.ISLBEvent
+	Event []byte `json:"event,omitempty"`
+	// Heartbeat an event without payload, Origin is alive
+	Heartbeat bool `json:"heartbeat,omitempty"`
+}
+
+// remoteOrigin an islb process heard of
+type remoteOrigin struct {
+	dc string
+	// last seq received
+	seq  uint64
+	last time.Time
+}
+
+// federation exchange the events posted to the islbs of every dc.
+// An islb only forwards the events posted to it, never the ones it got
+// from another islb, so an event can not loop between clusters whatever
+// the nats topology. The events coming back to their origin, or delivered
+// twice through redundant routes, are dropped. The islbs send heartbeats,
+// an origin gone silent is forgotten, and the records of its dc are purged
+// once no islb of the dc is left to remove them.
+type federation struct {
+	dc     string
+	origin string
+	nc     *nats.Conn
+	sub    *nats.Subscription
+	handle func(dc string, event *islb.ISLBEvent)
+	// gone purge the records of a dc whose islbs are all gone
+	gone func(dc string)
+	done chan struct{}
+
+	// sendLock numbers the events in the order they are published, the
+	// heartbeats are sent concurrently with them
+	sendLock sync.Mutex
+	seq      uint64
+
+	mu      sync.Mutex
+	origins map[string]*remoteOrigin
+}
+
+func newFederation(dc, origin string, nc *nats.Conn, handle func(dc string, event *islb.ISLBEvent), gone func(dc string)) (*federation, error) {
+	f := &federation{
+		dc:      dc,
+		origin:  origin,
+		nc:      nc,
+		handle:  handle,
+		gone:    gone,
+		done:    make(chan struct{}),
+		origins: make(map[string]*remoteOrigin),
+	}
+	var err error
+	f.sub, err = nc.Subscribe(federationSubject, f.receive)
+	if err != nil {
+		log.Errorf("nc.Subscribe(%v) err => %v", federationSubject, err)
+		return nil, err
+	}
+	go f.run()
+	return f, nil
+}
+
+// run send the heartbeats and forget the origins gone silent until closed
+func (f *federation) run() {
+	ticker := time.NewTicker(federationHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.done:
+			return
+		case now := <-ticker.C:
+			f.send(&federatedEvent{Heartbeat: true})
+			for _, dc := range f.expire(now) {
+				log.Infof("islb: no islb of dc %v left, purge its records", dc)
+				f.gone(dc)
+			}
+		}
+	}
+}
+
+// publish forward an event posted to this islb
+func (f *federation) publish(event *islb.ISLBEvent) {
+	data, err := protobuf.Marshal(event)
+	if err != nil {
+		log.Errorf("proto.Marshal err => %v", err)
+		return
+	}
+	f.send(&federatedEvent{Event: data})
+}
+
+// send number fe and publish it
+func (f *federation) send(fe *federatedEvent) {
+	f.sendLock.Lock()
+	defer f.sendLock.Unlock()
+
+	f.seq++
+	fe.DC, fe.Origin, fe.Seq = f.dc, f.origin, f.seq
+	msg, err := json.Marshal(fe)
+	if err != nil {
+		log.Errorf("json.Marshal err => %v", err)
+		return
+	}
+	if err := f.nc.Publish(federationSubject, msg); err != nil {
+		log.Errorf("nc.Publish(%v) err => %v", federationSubject, err)
+	}
+}
+
+func (f *federation) receive(msg *nats.Msg) {
+	var fe federatedEvent
+	if err := json.Unmarshal(msg.Data, &fe); err != nil {
+		log.Errorf("json.Unmarshal err => %v", err)
+		return
+	}
+	if fe.Origin == f.origin || !f.fresh(fe.DC, fe.Origin, fe.Seq, time.Now()) || fe.Heartbeat {
+		return
+	}
+	event := &islb.ISLBEvent{}
+	if err := protobuf.Unmarshal(fe.Event, event); err != nil {
+		log.Errorf("proto.Unmarshal err => %v", err)
+		return
+	}
+	log.Debugf("islb: federated event from %v (%v) => %v", fe.Origin, fe.DC, event)
+	f.handle(fe.DC, event)
+}
+
+// fresh return true the first time seq of origin is received
+func (f *federation) fresh(dc, origin string, seq uint64, now time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	o := f.origins[origin]
+	if o == nil {
+		o = &remoteOrigin{dc: dc}
+		f.origins[origin] = o
+	}
+	o.last = now
+	if seq <= o.seq {
+		return false
+	}
+	o.seq = seq
+	return true
+}
+
+// expire forget the origins not heard of for federationOriginTTL,
+// return the other dcs they leave without any islb
+func (f *federation) expire(now time.Time) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	expired := make(map[string]bool)
+	for origin, o := range f.origins {
+		if now.Sub(o.last) > federationOriginTTL {
+			delete(f.origins, origin)
+			expired[o.dc] = true
+		}
+	}
+	for _, o := range f.origins {
+		delete(expired, o.dc)
+	}
+	// the store of this dc is shared, the islbs left keep it up to date
+	delete(expired, f.dc)
+
+	var dcs []string
+	for dc := range expired {
+		dcs = append(dcs, dc)
+	}
+	return dcs
+}
+
+func (f *federation) close() {
+	close(f.done)
+	if err := f.sub.Unsubscribe(); err != nil {
+		log.Errorf("sub.Unsubscribe err => %v", err)
+	}
+}
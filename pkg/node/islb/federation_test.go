@@ -0,0 +1,84 @@
+package islb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	ion "github.com/pion/ion/proto/ion"
+	islb "github.com/pion/ion/proto/islb"
+	"github.com/stretchr/testify/assert"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+func federatedMsg(t *testing.T, dc, origin string, seq uint64, event *islb.ISLBEvent) *nats.Msg {
+	data, err := protobuf.Marshal(event)
+	assert.NoError(t, err)
+	msg, err := json.Marshal(&federatedEvent{DC: dc, Origin: origin, Seq: seq, Event: data})
+	assert.NoError(t, err)
+	return &nats.Msg{Subject: federationSubject, Data: msg}
+}
+
+func TestFederationReceive(t *testing.T) {
+	var got []string
+	f := &federation{
+		dc:      "dc1",
+		origin:  "islb-01/a",
+		origins: make(map[string]*remoteOrigin),
+		handle: func(dc string, event *islb.ISLBEvent) {
+			got = append(got, dc+"/"+event.GetStream().Uid)
+		},
+	}
+	stream := func(uid string) *islb.ISLBEvent {
+		return &islb.ISLBEvent{
+			Payload: &islb.ISLBEvent_Stream{
+				Stream: &ion.StreamEvent{Nid: "sfu-02", Sid: "room1", Uid: uid},
+			},
+		}
+	}
+
+	f.receive(federatedMsg(t, "dc2", "islb-02/b", 1, stream("u1")))
+	// delivered twice through another route
+	f.receive(federatedMsg(t, "dc2", "islb-02/b", 1, stream("u1")))
+	// back to its origin
+	f.receive(federatedMsg(t, "dc1", "islb-01/a", 1, stream("u2")))
+	f.receive(federatedMsg(t, "dc2", "islb-02/b", 2, stream("u3")))
+	// the same islb after a restart
+	f.receive(federatedMsg(t, "dc2", "islb-02/c", 1, stream("u4")))
+
+	assert.Equal(t, []string{"dc2/u1", "dc2/u3", "dc2/u4"}, got)
+}
+
+func TestFederationExpire(t *testing.T) {
+	f := &federation{
+		dc:      "dc1",
+		origin:  "islb-01/a",
+		origins: make(map[string]*remoteOrigin),
+		handle:  func(dc string, event *islb.ISLBEvent) {},
+	}
+	heartbeat := func(dc, origin string, seq uint64) *nats.Msg {
+		msg, err := json.Marshal(&federatedEvent{DC: dc, Origin: origin, Seq: seq, Heartbeat: true})
+		assert.NoError(t, err)
+		return &nats.Msg{Subject: federationSubject, Data: msg}
+	}
+
+	now := time.Now()
+	f.receive(heartbeat("dc1", "islb-02/b", 1))
+	f.receive(heartbeat("dc2", "islb-03/c", 1))
+	f.receive(heartbeat("dc3", "islb-04/d", 1))
+	f.receive(heartbeat("dc3", "islb-05/e", 1))
+	assert.Empty(t, f.expire(now))
+
+	// islb-05/e keeps dc3 alive
+	later := now.Add(federationOriginTTL + time.Second)
+	f.fresh("dc3", "islb-05/e", 2, later)
+	// the store of dc1 is shared, it is never purged
+	assert.Equal(t, []string{"dc2"}, f.expire(later))
+	assert.Len(t, f.origins, 1)
+
+	// the expired origins are forgotten
+	assert.Empty(t, f.expire(later))
+	assert.Equal(t, []string{"dc3"}, f.expire(later.Add(federationOriginTTL+time.Second)))
+	assert.Empty(t, f.origins)
+}
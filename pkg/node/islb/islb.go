@@ -1,7 +1,8 @@
 package islb
 
 import (
-	"errors"
+	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -17,6 +18,8 @@ import (
 
 const (
 	redisLongKeyTTL = 24 * time.Hour
+	// events kept for the watchers resuming a broken stream
+	replayBufferSize = 512
 )
 
 type global struct {
@@ -32,18 +35,39 @@ type natsConf struct {
 	URL string `mapstructure:"url"`
 }
 
+type storeConf struct {
+	// Backend "redis" (default) or "memory"
+	Backend string `mapstructure:"backend"`
+}
+
 type nodeConf struct {
-	NID string `mapstructure:"nid"`
+	NID      string `mapstructure:"nid"`
+	Selector string `mapstructure:"selector"`
+	// Secret signs the node info, shared by every node of the cluster
+	Secret string `mapstructure:"secret"`
+}
+
+type federationConf struct {
+	// Enable exchange the stream and session events with the islbs of the other dcs
+	Enable bool `mapstructure:"enable"`
+}
+
+type routingConf struct {
+	// Fallback the dcs by priority a dc falls back to when it has no node left
+	Fallback map[string][]string `mapstructure:"fallback"`
 }
 
 // Config for islb node
 type Config struct {
-	Global  global    `mapstructure:"global"`
-	Log     logConf   `mapstructure:"log"`
-	Nats    natsConf  `mapstructure:"nats"`
-	Node    nodeConf  `mapstructure:"node"`
-	Redis   db.Config `mapstructure:"redis"`
-	CfgFile string
+	Global     global         `mapstructure:"global"`
+	Log        logConf        `mapstructure:"log"`
+	Nats       natsConf       `mapstructure:"nats"`
+	Node       nodeConf       `mapstructure:"node"`
+	Store      storeConf      `mapstructure:"store"`
+	Redis      db.Config      `mapstructure:"redis"`
+	Routing    routingConf    `mapstructure:"routing"`
+	Federation federationConf `mapstructure:"federation"`
+	CfgFile    string
 }
 
 // ISLB represents islb node
@@ -51,7 +75,7 @@ type ISLB struct {
 	ion.Node
 	s        *islbServer
 	registry *Registry
-	redis    *db.Redis
+	store    db.Store
 }
 
 // NewISLB create a islb node instance
@@ -78,22 +102,51 @@ func (i *ISLB) Start(conf Config) error {
 		i.Close()
 		return err
 	}
+	i.Node.SetSecret(conf.Node.Secret)
+
+	switch conf.Store.Backend {
+	case db.BackendMemory:
+		// a single islb, nothing is shared with other islb instances
+		i.store = db.NewMemory()
+	case db.BackendRedis, "":
+		redis, err := db.NewRedis(conf.Redis)
+		if err != nil {
+			log.Errorf("db.NewRedis: error => %v", err)
+			i.Close()
+			return err
+		}
+		i.store = redis
+	default:
+		i.Close()
+		return fmt.Errorf("unknown store backend %v", conf.Store.Backend)
+	}
+
+	i.s = newISLBServer(conf, i, i.store)
+	pb.RegisterISLBServer(i.Node.ServiceRegistrar(), i.s)
 
-	i.redis = db.NewRedis(conf.Redis)
-	if i.redis == nil {
-		return errors.New("new redis error")
+	if conf.Federation.Enable {
+		i.s.federation, err = newFederation(conf.Global.Dc, i.s.id, i.Node.NatsConn(), i.s.handleFederatedEvent, i.s.purgeDC)
+		if err != nil {
+			i.Close()
+			return err
+		}
 	}
 
 	//registry for node discovery.
-	i.registry, err = NewRegistry(conf.Global.Dc, i.Node.NatsConn(), i.redis)
+	i.registry, err = NewRegistry(i.Node.NatsConn(), i.store, RegistryConfig{
+		DC:       conf.Global.Dc,
+		Secret:   conf.Node.Secret,
+		Fallback: conf.Routing.Fallback,
+		Selector: ion.NewSelector(conf.Node.Selector),
+		NodeDown: i.s.handleNodeDown,
+		Cascade:  i.s.cascadeSession,
+	})
 	if err != nil {
 		log.Errorf("%v", err)
+		i.Close()
 		return err
 	}
 
-	i.s = newISLBServer(conf, i, i.redis)
-	pb.RegisterISLBServer(i.Node.ServiceRegistrar(), i.s)
-
 	// Register reflection service on nats-rpc server.
 	reflection.Register(i.Node.ServiceRegistrar().(*nrpc.Server))
 
@@ -116,6 +169,7 @@ func (i *ISLB) Start(conf Config) error {
 	}()
 
 	//Watch ALL nodes.
+	i.Node.OnNodeDown(i.s.handleNodeDown)
 	go func() {
 		err := i.Node.Watch(proto.ServiceALL)
 		if err != nil {
@@ -126,13 +180,27 @@ func (i *ISLB) Start(conf Config) error {
 	return nil
 }
 
+// Drain deregister the islb node and close it
+func (i *ISLB) Drain(ctx context.Context) error {
+	err := i.Node.Drain(ctx)
+	i.Close()
+	return err
+}
+
 // Close all
 func (i *ISLB) Close() {
-	i.Node.Close()
-	if i.redis != nil {
-		i.redis.Close()
+	if i.s != nil && i.s.federation != nil {
+		i.s.federation.close()
+		i.s.federation = nil
 	}
+	i.Node.Close()
+	// the registry watches and writes the store until it is closed
 	if i.registry != nil {
 		i.registry.Close()
+		i.registry = nil
+	}
+	if i.store != nil {
+		i.store.Close()
+		i.store = nil
 	}
 }
@@ -1,25 +1,83 @@
 package islb
 
 import (
-	"sync"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
 	"github.com/cloudwebrtc/nats-discovery/pkg/registry"
 	"github.com/nats-io/nats.go"
 	log "github.com/pion/ion-log"
 	"github.com/pion/ion/pkg/db"
+	"github.com/pion/ion/pkg/ion"
 	"github.com/pion/ion/pkg/proto"
 )
 
+const (
+	// node records are kept in the store under
+	// key = ion-node/dc/service/nid
+	// value = discovery.Node json
+	nodeKeyPrefix = "ion-node/"
+	// a node is forgotten if it misses its keepalives
+	nodeKeyTTL = time.Duration(discovery.DefaultExpire) * time.Second
+	// the islb electing the edge of a session holds its claim
+	// key = ion-cascade/dc/sid
+	// value = nid of the host
+	cascadeClaimPrefix = "ion-cascade/"
+	cascadeClaimTTL    = 5 * time.Second
+	// the peers sent to a node, counted until its load shows them
+	// key = ion-admit/dc/nid
+	// value = {peers: ${count}, sessions: ${count}}
+	admitKeyPrefix = "ion-admit/"
+	// a few keepalives of the node, its load is republished with each one
+	admitTTL = 5 * time.Second
+)
+
+// Registry keeps the nodes of the cluster in the store, so every
+// islb instance sharing a redis store sees the same nodes.
 type Registry struct {
-	dc    string
-	redis *db.Redis
-	reg   *registry.Registry
-	mutex sync.Mutex
-	nodes map[string]discovery.Node
+	dc       string
+	store    db.Store
+	reg      *registry.Registry
+	selector ion.Selector
+	// nodeDown is called when a node is deleted or expires
+	nodeDown func(node discovery.Node)
+	// cascade relays session sid between the sfus nids, edge just joined them
+	cascade func(sid, edge string, nids []string) error
+	cancel  context.CancelFunc
+	// watched closed once watchExpired is done with the store
+	watched chan struct{}
+	// secret verifies the node credentials, empty to accept any node
+	secret string
+	// fallback the dcs searched by priority when a dc has no node left,
+	// a dc without a list falls back to any other dc
+	fallback map[string][]string
+}
+
+// RegistryConfig the settings and callbacks of a Registry
+type RegistryConfig struct {
+	// DC the dc of the islb, the dc of the callers without caller_dc
+	DC string
+	// Secret verifies the node credentials, empty to accept any node
+	Secret string
+	// Fallback the dcs searched by priority when a dc has no node left
+	Fallback map[string][]string
+	// Selector picks the node of a new session
+	Selector ion.Selector
+	// NodeDown is called when a node is deleted or expires
+	NodeDown func(node discovery.Node)
+	// Cascade relays session sid between the sfus nids, edge just joined them
+	Cascade func(sid, edge string, nids []string) error
 }
 
-func NewRegistry(dc string, nc *nats.Conn, redis *db.Redis) (*Registry, error) {
+func NewRegistry(nc *nats.Conn, store db.Store, conf RegistryConfig) (*Registry, error) {
+	if conf.Secret == "" {
+		log.Warnf("islb: no node secret, any node reaching nats can register")
+	}
 
 	reg, err := registry.NewRegistry(nc, discovery.DefaultExpire)
 	if err != nil {
@@ -28,12 +86,21 @@ func NewRegistry(dc string, nc *nats.Conn, redis *db.Redis) (*Registry, error) {
 	}
 
 	r := &Registry{
-		dc:    dc,
-		reg:   reg,
-		redis: redis,
-		nodes: make(map[string]discovery.Node),
+		dc:       conf.DC,
+		secret:   conf.Secret,
+		fallback: conf.Fallback,
+		reg:      reg,
+		store:    store,
+		selector: conf.Selector,
+		nodeDown: conf.NodeDown,
+		cascade:  conf.Cascade,
+		watched:  make(chan struct{}),
 	}
 
+	var ctx context.Context
+	ctx, r.cancel = context.WithCancel(context.Background())
+	go r.watchExpired(ctx)
+
 	err = reg.Listen(r.handleNodeAction, r.handleGetNodes)
 
 	if err != nil {
@@ -45,36 +112,292 @@ func NewRegistry(dc string, nc *nats.Conn, redis *db.Redis) (*Registry, error) {
 	return r, nil
 }
 
+// Close stop the registry, it no longer uses the store once Close returns
 func (r *Registry) Close() {
+	r.cancel()
 	r.reg.Close()
+	<-r.watched
+}
+
+// watchExpired report the nodes whose record expired in the store,
+// they went away without a discovery.Delete.
+func (r *Registry) watchExpired(ctx context.Context) {
+	defer close(r.watched)
+	events, err := r.store.Watch(ctx, nodeKeyPrefix+"*")
+	if err != nil {
+		log.Warnf("islb: node expirations not watched, stale streams stay until their ttl: %v", err)
+		return
+	}
+	for event := range events {
+		if event.Op != db.OpExpired || r.nodeDown == nil {
+			continue
+		}
+		// key = ion-node/dc/service/nid
+		strs := strings.Split(strings.TrimPrefix(event.Key, nodeKeyPrefix), "/")
+		if len(strs) != 3 {
+			continue
+		}
+		log.Infof("islb: node %v expired", event.Key)
+		r.nodeDown(discovery.Node{DC: strs[0], Service: strs[1], NID: strs[2]})
+	}
+}
+
+func cascadeClaimKey(dc, sid string) string {
+	return cascadeClaimPrefix + dc + "/" + sid
+}
+
+func nodeKey(node discovery.Node) string {
+	return nodeKeyPrefix + node.DC + "/" + node.Service + "/" + node.NID
 }
 
 // handleNodeAction handle all Node from service discovery.
 // This callback can observe all nodes in the ion cluster,
-// TODO: Upload all node information to redis DB so that info
-// can be shared when there are more than one ISLB in the later.
+// node info is uploaded to the store so that it is shared by
+// all the ISLBs of the cluster.
 func (r *Registry) handleNodeAction(action discovery.Action, node discovery.Node) (bool, error) {
-	//Add authentication here
 	log.Debugf("handleNode: service %v, action %v => id %v, RPC %v", node.Service, action, node.ID(), node.RPC)
 
-	//TODO: Put node info into the redis.
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	if err := r.authenticate(node); err != nil {
+		log.Warnf("islb: reject %v of node %v: %v", action, node.ID(), err)
+		return false, err
+	}
 
 	switch action {
 	case discovery.Save:
 		fallthrough
 	case discovery.Update:
-		r.nodes[node.ID()] = node
+		r.resetReservations(node)
+		data, err := json.Marshal(node)
+		if err != nil {
+			log.Errorf("json.Marshal err => %v", err)
+			return false, err
+		}
+		err = r.store.Set(nodeKey(node), string(data), nodeKeyTTL)
+		if err != nil {
+			log.Errorf("r.store.Set failed %v", err)
+			return false, err
+		}
 	case discovery.Delete:
-		delete(r.nodes, node.ID())
+		err := r.store.Del(nodeKey(node))
+		if err != nil {
+			log.Errorf("r.store.Del failed %v", err)
+			return false, err
+		}
+		if r.nodeDown != nil {
+			r.nodeDown(node)
+		}
 	}
 
 	return true, nil
 }
 
+// authenticate check the credential of node, and that no other
+// process holds the same NID while it is live, nor was it signed
+// before the live record.
+func (r *Registry) authenticate(node discovery.Node) error {
+	if r.secret != "" {
+		if err := ion.VerifyNode(r.secret, node); err != nil {
+			return err
+		}
+	}
+
+	value, ok := r.store.Get(nodeKey(node)).(string)
+	if !ok || value == "" {
+		return nil
+	}
+	var live discovery.Node
+	if err := json.Unmarshal([]byte(value), &live); err != nil {
+		return nil
+	}
+	origin, liveOrigin := ion.NodeOrigin(node), ion.NodeOrigin(live)
+	if origin != "" && liveOrigin != "" && origin != liveOrigin {
+		return fmt.Errorf("nid %v is already live from origin %v", node.NID, liveOrigin)
+	}
+	// a record replayed within the time window is older than the live one
+	if ion.NodeTime(node).Before(ion.NodeTime(live)) {
+		return ion.ErrNodeStale
+	}
+	return nil
+}
+
+// getNodes load the live nodes of service from the store, "*" for all nodes
+func (r *Registry) getNodes(service string) []discovery.Node {
+	pattern := nodeKeyPrefix + "*/" + db.EscapeGlob(service) + "/*"
+	if service == proto.ServiceALL {
+		pattern = nodeKeyPrefix + "*"
+	}
+
+	var nodes []discovery.Node
+	keys := r.store.Keys(pattern)
+	for i, value := range r.store.MGet(keys) {
+		key := keys[i]
+		if value == "" {
+			// expired in the meantime
+			continue
+		}
+		var node discovery.Node
+		if err := json.Unmarshal([]byte(value), &node); err != nil {
+			log.Errorf("json.Unmarshal %v err => %v", key, err)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// findSessionNode find the live sfu node hosting sid from the session table,
+// or the stream records
+// key = dc/nid/sid/uid
+func (r *Registry) findSessionNode(nid, sid string) (discovery.Node, bool) {
+	if info, found := getSession(r.store, r.dc, sid); found && (nid == "*" || nid == info.NID) {
+		if node, found := r.getNode(proto.ServiceSFU, info.NID); found {
+			return node, true
+		}
+	}
+
+	if nid != "*" {
+		nid = db.EscapeGlob(nid)
+	}
+	mkey := r.dc + "/" + nid + "/" + db.EscapeGlob(sid) + "/*"
+	log.Debugf("islb.findSessionNode: mkey => %v", mkey)
+	for _, key := range r.store.Keys(mkey) {
+		strs := strings.Split(key, "/")
+		if len(strs) < 4 {
+			continue
+		}
+		if node, found := r.getNode(proto.ServiceSFU, strs[1]); found {
+			return node, true
+		}
+		log.Debugf("islb.findSessionNode: %v hosted by a dead node", key)
+	}
+	return discovery.Node{}, false
+}
+
+// cascadeSession return a sfu with room left for session sid whose host is full:
+// an edge already relaying the session, or another sfu of the dc of the host,
+// which the session is relayed to from now on. The edge is elected by the islb
+// holding the cascade claim of the session, the joins racing with the election
+// do not wait for it and go to the host holding the claim, over its capacity.
+func (r *Registry) cascadeSession(sid string, host discovery.Node) (discovery.Node, error) {
+	if edge, found, err := r.sessionEdge(sid, host); err != nil || found {
+		return edge, err
+	}
+
+	claimed, err := r.store.SetNX(cascadeClaimKey(r.dc, sid), host.NID, cascadeClaimTTL)
+	if err != nil {
+		log.Errorf("r.store.SetNX failed %v", err)
+		return discovery.Node{}, err
+	}
+	if claimed {
+		return r.electEdge(sid, host)
+	}
+	log.Infof("islb: session %v is being cascaded, join its host %v", sid, host.NID)
+	return host, nil
+}
+
+// sessionEdge return an edge of session sid with room left
+func (r *Registry) sessionEdge(sid string, host discovery.Node) (discovery.Node, bool, error) {
+	info, found := getSession(r.store, r.dc, sid)
+	if !found || info.NID != host.NID || r.cascade == nil {
+		log.Warnf("islb: session %v is full, node %v at its capacity", sid, host.NID)
+		return discovery.Node{}, false, ion.ErrClusterFull
+	}
+	for _, node := range r.getNodes(proto.ServiceSFU) {
+		if _, edge := info.Edges[node.NID]; edge && r.canAdmit(node, false) {
+			return node, true, nil
+		}
+	}
+	return discovery.Node{}, false, nil
+}
+
+// electEdge pick the edge session sid is cascaded to, the claim of the
+// session is held. The edge is saved before the claim is released, and the
+// sfus are told in the background, the peer joining the edge is relayed
+// when it joins.
+func (r *Registry) electEdge(sid string, host discovery.Node) (discovery.Node, error) {
+	defer func() {
+		if err := r.store.Del(cascadeClaimKey(r.dc, sid)); err != nil {
+			log.Errorf("r.store.Del failed %v", err)
+		}
+	}()
+
+	// an edge elected since the session was read
+	if edge, found, err := r.sessionEdge(sid, host); err != nil || found {
+		return edge, err
+	}
+	info, _ := getSession(r.store, r.dc, sid)
+
+	var candidates []discovery.Node
+	for _, node := range r.getNodes(proto.ServiceSFU) {
+		if _, edge := info.Edges[node.NID]; edge || node.NID == host.NID || node.DC != host.DC {
+			continue
+		}
+		if !ion.IsDraining(node) && r.canAdmit(node, true) {
+			candidates = append(candidates, node)
+		}
+	}
+	if len(candidates) == 0 {
+		log.Warnf("islb: session %v is full, no sfu left to cascade it to", sid)
+		return discovery.Node{}, ion.ErrClusterFull
+	}
+
+	edge := r.selector.Select(sid, candidates)
+	key := sessionKey(r.dc, sid)
+	if err := r.store.HSetTTL(key, edgeField(edge.NID), 0, redisLongKeyTTL); err != nil {
+		log.Errorf("r.store.HSetTTL failed %v", err)
+		return discovery.Node{}, err
+	}
+	info.Edges[edge.NID] = 0
+	log.Infof("islb: cascade session %v from %v to %v", sid, host.NID, edge.NID)
+	go func(nids []string) {
+		if err := r.cascade(sid, edge.NID, nids); err != nil {
+			log.Errorf("islb: cascade session %v to %v failed: %v", sid, edge.NID, err)
+			if err := r.store.HDel(key, edgeField(edge.NID)); err != nil {
+				log.Errorf("r.store.HDel failed %v", err)
+			}
+		}
+	}(info.nids())
+	return edge, nil
+}
+
+// getNode get a live node by service and nid
+func (r *Registry) getNode(service, nid string) (discovery.Node, bool) {
+	for _, node := range r.getNodes(service) {
+		if node.NID == nid {
+			return node, true
+		}
+	}
+	return discovery.Node{}, false
+}
+
+// findNodes look up the live nodes matching a FindNode query,
+// a sid lookup returns the sfu hosting the session.
+func (r *Registry) findNodes(service, nid, sid string) []discovery.Node {
+	if service == "" {
+		service = proto.ServiceALL
+	}
+
+	if sid != "" && (service == proto.ServiceSFU || service == proto.ServiceALL) {
+		if nid == "" {
+			nid = "*"
+		}
+		if node, found := r.findSessionNode(nid, sid); found {
+			return []discovery.Node{node}
+		}
+		return nil
+	}
+
+	if nid != "" {
+		if node, found := r.getNode(service, nid); found {
+			return []discovery.Node{node}
+		}
+		return nil
+	}
+
+	return r.getNodes(service)
+}
+
 func (r *Registry) handleGetNodes(service string, params map[string]interface{}) ([]discovery.Node, error) {
-	//Add load balancing here.
 	log.Infof("Get node by %v, params %v", service, params)
 
 	if service == proto.ServiceSFU {
@@ -88,24 +411,173 @@ func (r *Registry) handleGetNodes(service string, params map[string]interface{})
 			sid = val.(string)
 		}
 
-		// find node by nid/sid from reids
-		mkey := r.dc + "/" + nid + "/" + sid
-		log.Infof("islb.FindNode: mkey => %v", mkey)
-		for _, key := range r.redis.Keys(mkey) {
-			value := r.redis.Get(key)
-			log.Debugf("key: %v, value: %v", key, value)
+		// a session stays on the sfu already hosting it,
+		// only new sessions are load balanced.
+		if sid != "" {
+			if node, found := r.findSessionNode(nid, sid); found {
+				log.Infof("islb: session %v is hosted by %v", sid, node.NID)
+				if !r.canAdmit(node, false) {
+					// the session outgrew its sfu
+					edge, err := r.cascadeSession(sid, node)
+					if err != nil {
+						return nil, err
+					}
+					r.reserve(edge, false)
+					return []discovery.Node{edge}, nil
+				}
+				r.reserve(node, false)
+				return []discovery.Node{node}, nil
+			}
+		}
+
+		if nid != "*" {
+			if node, found := r.getNode(proto.ServiceSFU, nid); found {
+				return []discovery.Node{node}, nil
+			}
 		}
 	}
 
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	dcs, anyDC := r.routeDCs(params)
+	rank := func(node discovery.Node) int {
+		for i, dc := range dcs {
+			if node.DC == dc {
+				return i
+			}
+		}
+		if anyDC {
+			return len(dcs)
+		}
+		return -1
+	}
 
 	nodesResp := []discovery.Node{}
-	for _, item := range r.nodes {
-		if item.Service == service || service == "*" {
+	saturated := 0
+	best := -1
+	for _, item := range r.getNodes(service) {
+		if service == proto.ServiceALL {
 			nodesResp = append(nodesResp, item)
+			continue
+		}
+		// only the nodes of the best dc with room left are returned.
+		i := rank(item)
+		if i < 0 || (best >= 0 && i > best) {
+			continue
+		}
+		// draining nodes keep their sessions but get no new ones.
+		if ion.IsDraining(item) {
+			continue
+		}
+		// so do the nodes at their capacity.
+		if !r.canAdmit(item, true) {
+			saturated++
+			continue
+		}
+		if best < 0 || i < best {
+			best = i
+			nodesResp = nodesResp[:0]
+		}
+		nodesResp = append(nodesResp, item)
+	}
+
+	if len(nodesResp) == 0 && saturated > 0 {
+		log.Warnf("islb: all the %v nodes of %v are at their capacity", saturated, service)
+		return nil, ion.ErrClusterFull
+	}
+
+	// callers take the first node, put the selected one there.
+	if service != proto.ServiceALL && len(nodesResp) > 1 {
+		sid, _ := params["sid"].(string)
+		selected := r.selector.Select(sid, nodesResp)
+		for i, item := range nodesResp {
+			if item.ID() == selected.ID() {
+				nodesResp[0], nodesResp[i] = nodesResp[i], nodesResp[0]
+				break
+			}
 		}
 	}
+	if sid, _ := params["sid"].(string); service == proto.ServiceSFU && sid != "" && len(nodesResp) > 0 {
+		r.reserve(nodesResp[0], true)
+	}
 
 	return nodesResp, nil
 }
+
+func admitKey(node discovery.Node) string {
+	return admitKeyPrefix + node.DC + "/" + node.NID
+}
+
+// reserve count a peer sent to node until the load of node shows it,
+// so the joins of a burst do not all see the same load
+func (r *Registry) reserve(node discovery.Node, newSession bool) {
+	if ion.GetNodeCapacity(node) == (ion.Capacity{}) {
+		return
+	}
+	key := admitKey(node)
+	if _, err := r.store.HIncrTTL(key, "peers", 1, admitTTL); err != nil {
+		log.Errorf("r.store.HIncrTTL failed %v", err)
+		return
+	}
+	if newSession {
+		if _, err := r.store.HIncrTTL(key, "sessions", 1, admitTTL); err != nil {
+			log.Errorf("r.store.HIncrTTL failed %v", err)
+		}
+	}
+}
+
+// resetReservations forget the peers reserved on node once a keepalive
+// reports a new load, the load counts the peers that joined since
+func (r *Registry) resetReservations(node discovery.Node) {
+	if ion.GetNodeCapacity(node) == (ion.Capacity{}) {
+		return
+	}
+	value, _ := r.store.Get(nodeKey(node)).(string)
+	var live discovery.Node
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), &live); err != nil {
+			log.Errorf("json.Unmarshal %v err => %v", nodeKey(node), err)
+		}
+	}
+	load, liveLoad := ion.GetNodeLoad(node), ion.GetNodeLoad(live)
+	if value != "" && load.Peers == liveLoad.Peers && load.Sessions == liveLoad.Sessions {
+		return
+	}
+	if err := r.store.Del(admitKey(node)); err != nil {
+		log.Errorf("r.store.Del failed %v", err)
+	}
+}
+
+// canAdmit return true if node has room for one more peer, in a new session
+// if newSession is set, the peers reserved on node included
+func (r *Registry) canAdmit(node discovery.Node, newSession bool) bool {
+	capacity := ion.GetNodeCapacity(node)
+	if capacity == (ion.Capacity{}) {
+		return true
+	}
+	load := ion.GetNodeLoad(node)
+	reserved := r.store.HGetAll(admitKey(node))
+	peers, _ := strconv.Atoi(reserved["peers"])
+	sessions, _ := strconv.Atoi(reserved["sessions"])
+	load.Peers += peers
+	load.Sessions += sessions
+	return capacity.Admits(load, newSession)
+}
+
+// routeDCs return the dcs to take new sessions from, by preference, and whether
+// any other dc may be used after them. An explicit "dc" param is the only dc
+// searched, otherwise the caller dc ("caller_dc", the islb dc if missing) comes
+// first, followed by its fallback list.
+func (r *Registry) routeDCs(params map[string]interface{}) ([]string, bool) {
+	if dc, _ := params["dc"].(string); dc != "" {
+		return []string{dc}, false
+	}
+
+	caller, _ := params["caller_dc"].(string)
+	if caller == "" {
+		caller = r.dc
+	}
+	fallback, found := r.fallback[caller]
+	if !found {
+		return []string{caller}, true
+	}
+	return append([]string{caller}, fallback...), false
+}
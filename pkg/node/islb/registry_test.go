@@ -0,0 +1,285 @@
+package islb
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
+	"github.com/pion/ion/pkg/db"
+	"github.com/pion/ion/pkg/ion"
+	"github.com/pion/ion/pkg/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRegistry(t *testing.T, fallback map[string][]string, nodes ...discovery.Node) *Registry {
+	r := &Registry{
+		dc:       "dc1",
+		store:    db.NewMemory(),
+		selector: ion.NewSelector(ion.SelectorRoundRobin),
+		fallback: fallback,
+	}
+	for _, node := range nodes {
+		data, err := json.Marshal(node)
+		assert.NoError(t, err)
+		assert.NoError(t, r.store.Set(nodeKey(node), string(data), nodeKeyTTL))
+	}
+	return r
+}
+
+func newTestNode(dc, nid string, params map[string]string) discovery.Node {
+	return discovery.Node{
+		DC:      dc,
+		Service: proto.ServiceSFU,
+		NID:     nid,
+		RPC:     discovery.RPC{Protocol: discovery.NGRPC, Params: params},
+	}
+}
+
+func nids(nodes []discovery.Node) []string {
+	var ids []string
+	for _, node := range nodes {
+		ids = append(ids, node.NID)
+	}
+	return ids
+}
+
+func TestHandleGetNodesDC(t *testing.T) {
+	full := map[string]string{"sessions": "1", "max_sessions": "1"}
+	r := newTestRegistry(t, map[string][]string{"dc3": {"dc2"}},
+		newTestNode("dc1", "sfu-01", nil),
+		newTestNode("dc2", "sfu-02", nil),
+		newTestNode("dc3", "sfu-03", full),
+		newTestNode("dc4", "sfu-04", nil),
+	)
+	defer r.store.Close()
+
+	nodes, err := r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sfu-01"}, nids(nodes))
+
+	nodes, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"caller_dc": "dc2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sfu-02"}, nids(nodes))
+
+	// dc3 is full and falls back to dc2 only
+	nodes, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"caller_dc": "dc3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sfu-02"}, nids(nodes))
+
+	nodes, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"dc": "dc4", "caller_dc": "dc1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sfu-04"}, nids(nodes))
+
+	_, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"dc": "dc3"})
+	assert.True(t, ion.IsClusterFull(err))
+
+	nodes, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"dc": "dc5"})
+	assert.NoError(t, err)
+	assert.Empty(t, nodes)
+
+	// a dc without a fallback list falls back to any dc
+	nodes, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"caller_dc": "dc5"})
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 3)
+}
+
+func TestHandleGetNodesSession(t *testing.T) {
+	r := newTestRegistry(t, nil,
+		newTestNode("dc1", "sfu-01", map[string]string{"sessions": "5"}),
+		newTestNode("dc1", "sfu-02", map[string]string{"sessions": "0"}),
+	)
+	defer r.store.Close()
+	r.selector = ion.NewSelector(ion.SelectorLeastSessions)
+	assert.NoError(t, r.store.HSetTTL(sessionKey("dc1", "room1"), "nid", "sfu-01", redisLongKeyTTL))
+
+	// the session stays on its host, though the selector prefers sfu-02
+	nodes, err := r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": "room1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sfu-01"}, nids(nodes))
+
+	// a session with no host is placed by the selector
+	nodes, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": "room2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "sfu-02", nodes[0].NID)
+}
+
+func TestHandleGetNodesCascade(t *testing.T) {
+	full := map[string]string{"peers": "2", "max_peers": "2"}
+	r := newTestRegistry(t, nil,
+		newTestNode("dc1", "sfu-01", full),
+		newTestNode("dc1", "sfu-02", nil),
+		newTestNode("dc2", "sfu-03", nil),
+	)
+	defer r.store.Close()
+	cascades := make(chan string, 10)
+	r.cascade = func(sid, edge string, nids []string) error {
+		cascades <- sid + "/" + edge
+		return nil
+	}
+	assert.NoError(t, r.store.HSetTTL(sessionKey("dc1", "room1"), "nid", "sfu-01", redisLongKeyTTL))
+
+	// the host is full, the session is relayed to another sfu of its dc
+	nodes, err := r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": "room1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sfu-02"}, nids(nodes))
+	info, found := getSession(r.store, "dc1", "room1")
+	assert.True(t, found)
+	assert.Equal(t, []string{"sfu-01", "sfu-02"}, info.nids())
+	assert.Equal(t, "room1/sfu-02", <-cascades)
+
+	// the next peers join the edge without cascading again
+	nodes, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": "room1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sfu-02"}, nids(nodes))
+	assert.Empty(t, cascades)
+	assert.Equal(t, "", r.store.Get(cascadeClaimKey("dc1", "room1")))
+}
+
+func TestHandleGetNodesCascadePending(t *testing.T) {
+	full := map[string]string{"peers": "2", "max_peers": "2"}
+	r := newTestRegistry(t, nil,
+		newTestNode("dc1", "sfu-01", full),
+		newTestNode("dc1", "sfu-02", nil),
+	)
+	defer r.store.Close()
+	r.cascade = func(sid, edge string, nids []string) error {
+		t.Error("cascaded while the claim is held")
+		return nil
+	}
+	assert.NoError(t, r.store.HSetTTL(sessionKey("dc1", "room1"), "nid", "sfu-01", redisLongKeyTTL))
+
+	// another islb is electing the edge, the join goes to the host at once
+	claimed, err := r.store.SetNX(cascadeClaimKey("dc1", "room1"), "sfu-01", cascadeClaimTTL)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	nodes, err := r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": "room1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sfu-01"}, nids(nodes))
+}
+
+func TestHandleGetNodesCascadeConcurrent(t *testing.T) {
+	full := map[string]string{"peers": "2", "max_peers": "2"}
+	r := newTestRegistry(t, nil,
+		newTestNode("dc1", "sfu-01", full),
+		newTestNode("dc1", "sfu-02", nil),
+		newTestNode("dc1", "sfu-03", nil),
+		newTestNode("dc1", "sfu-04", nil),
+	)
+	defer r.store.Close()
+	cascades := make(chan string, 10)
+	r.cascade = func(sid, edge string, nids []string) error {
+		cascades <- edge
+		return nil
+	}
+	assert.NoError(t, r.store.HSetTTL(sessionKey("dc1", "room1"), "nid", "sfu-01", redisLongKeyTTL))
+
+	// the joins racing to a full host get the one edge elected,
+	// or the host while the election is pending
+	var wg sync.WaitGroup
+	edges := make(chan string, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nodes, err := r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": "room1"})
+			assert.NoError(t, err)
+			assert.Len(t, nodes, 1)
+			edges <- nodes[0].NID
+		}()
+	}
+	wg.Wait()
+	close(edges)
+
+	edge := <-cascades
+	for nid := range edges {
+		assert.Contains(t, []string{edge, "sfu-01"}, nid)
+	}
+	info, _ := getSession(r.store, "dc1", "room1")
+	assert.Len(t, info.Edges, 1)
+	assert.Empty(t, cascades)
+}
+
+func TestFindSessionNodeGlob(t *testing.T) {
+	r := newTestRegistry(t, nil, newTestNode("dc1", "sfu-01", nil))
+	defer r.store.Close()
+	assert.NoError(t, r.store.Set("dc1/sfu-01/[z-a]/u1", "[]", 0))
+
+	// the sids of the clients are not patterns
+	node, found := r.findSessionNode("*", "[z-a]")
+	assert.True(t, found)
+	assert.Equal(t, "sfu-01", node.NID)
+	_, found = r.findSessionNode("*", "*")
+	assert.False(t, found)
+}
+
+func TestHandleNodeActionReplay(t *testing.T) {
+	r := newTestRegistry(t, nil)
+	defer r.store.Close()
+	r.secret = "s3cret"
+
+	signed := func(at time.Time) discovery.Node {
+		node := newTestNode("dc1", "sfu-01", map[string]string{
+			"origin": "o1",
+			"ts":     strconv.FormatInt(at.Unix(), 10),
+		})
+		node.RPC.Params["token"] = ion.SignNode(r.secret, node)
+		return node
+	}
+
+	now := time.Now()
+	first := signed(now.Add(-time.Second))
+	_, err := r.handleNodeAction(discovery.Save, first)
+	assert.NoError(t, err)
+	_, err = r.handleNodeAction(discovery.Update, signed(now))
+	assert.NoError(t, err)
+
+	// a captured record can't roll the live one back, nor outlive the window
+	_, err = r.handleNodeAction(discovery.Update, first)
+	assert.Equal(t, ion.ErrNodeStale, err)
+	_, err = r.handleNodeAction(discovery.Delete, signed(now))
+	assert.NoError(t, err)
+	_, err = r.handleNodeAction(discovery.Save, signed(now.Add(-2*ion.NodeTimeWindow)))
+	assert.Equal(t, ion.ErrNodeStale, err)
+	assert.Equal(t, "", r.store.Get(nodeKey(first)))
+}
+
+func TestHandleGetNodesBurst(t *testing.T) {
+	r := newTestRegistry(t, nil,
+		newTestNode("dc1", "sfu-01", map[string]string{"peers": "0", "max_peers": "2"}),
+		newTestNode("dc1", "sfu-02", map[string]string{"sessions": "0", "max_sessions": "1"}),
+	)
+	defer r.store.Close()
+
+	// the load of the nodes is not republished during the burst
+	var got []string
+	for _, sid := range []string{"room1", "room2", "room3"} {
+		nodes, err := r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": sid})
+		assert.NoError(t, err)
+		got = append(got, nodes[0].NID)
+	}
+	assert.ElementsMatch(t, []string{"sfu-01", "sfu-01", "sfu-02"}, got)
+
+	_, err := r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": "room4"})
+	assert.True(t, ion.IsClusterFull(err))
+
+	// the nodes are not reserved by the callers without a session
+	nodes, err := r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{})
+	assert.True(t, ion.IsClusterFull(err))
+	assert.Empty(t, nodes)
+
+	// a keepalive with the same load keeps the reservations,
+	// a new load replaces them
+	_, err = r.handleNodeAction(discovery.Update, newTestNode("dc1", "sfu-01", map[string]string{"peers": "0", "max_peers": "2"}))
+	assert.NoError(t, err)
+	_, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": "room4"})
+	assert.True(t, ion.IsClusterFull(err))
+	_, err = r.handleNodeAction(discovery.Update, newTestNode("dc1", "sfu-01", map[string]string{"peers": "1", "max_peers": "2"}))
+	assert.NoError(t, err)
+	nodes, err = r.handleGetNodes(proto.ServiceSFU, map[string]interface{}{"sid": "room4"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sfu-01"}, nids(nodes))
+	assert.Equal(t, map[string]string{"peers": "1", "sessions": "1"}, r.store.HGetAll(admitKey(nodes[0])))
+}
@@ -2,28 +2,91 @@ package islb
 
 import (
 	"context"
+	"strings"
+	"sync"
 
+	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
 	log "github.com/pion/ion-log"
 	"github.com/pion/ion/pkg/db"
+	"github.com/pion/ion/pkg/proto"
+	"github.com/pion/ion/pkg/util"
 	ion "github.com/pion/ion/proto/ion"
 	islb "github.com/pion/ion/proto/islb"
 	"github.com/square/go-jose/v3/json"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type islbServer struct {
 	islb.UnimplementedISLBServer
-	redis    *db.Redis
-	islb     *ISLB
-	conf     Config
-	watchers map[string]islb.ISLB_WatchISLBEventServer
+	store db.Store
+	islb  *ISLB
+	conf  Config
+
+	watchLock sync.RWMutex
+	watchers  map[*watcher]struct{}
+
+	// id the islb process numbering the events, seq only orders its own events
+	id string
+	// seqLock orders the events, a subscriber gets its snapshot
+	// or replay before any event that comes after it, it is never
+	// held while reading the store
+	seqLock sync.Mutex
+	seq     uint64
+	// the last replayBufferSize events, for watchers resuming a broken stream
+	history []*islb.ISLBEvent
+
+	// purgeLock serializes the purges of the same node
+	// reported both by the registry and the watch
+	purgeLock sync.Mutex
+
+	// federation forwards the events to the islbs of the other dcs, nil if disabled
+	federation *federation
 }
 
-func newISLBServer(conf Config, in *ISLB, redis *db.Redis) *islbServer {
+func newISLBServer(conf Config, in *ISLB, store db.Store) *islbServer {
 	return &islbServer{
+		id:       in.NID + "/" + in.Origin(),
 		conf:     conf,
 		islb:     in,
-		redis:    redis,
-		watchers: make(map[string]islb.ISLB_WatchISLBEventServer),
+		store:    store,
+		watchers: make(map[*watcher]struct{}),
+	}
+}
+
+//FindNode answer with the nodes hosting a session or a service
+//sid: the sfu node hosting the session, if any
+//nid: the node with this nid
+//service: all the live nodes of the service, "*" or empty for every node
+func (s *islbServer) FindNode(ctx context.Context, req *islb.FindNodeRequest) (*islb.FindNodeReply, error) {
+	log.Infof("ISLBServer.FindNode req => %v", req)
+	registry := s.islb.registry
+	if registry == nil {
+		return nil, status.Errorf(codes.Unavailable, "islb registry not ready")
+	}
+
+	nodes := registry.findNodes(req.Service, req.Nid, req.Sid)
+	reply := &islb.FindNodeReply{}
+	for _, node := range nodes {
+		reply.Nodes = append(reply.Nodes, toIonNode(node))
+	}
+	return reply, nil
+}
+
+func toIonNode(node discovery.Node) *ion.Node {
+	params := make(map[string]string, len(node.RPC.Params))
+	for k, v := range node.RPC.Params {
+		params[k] = v
+	}
+	return &ion.Node{
+		Dc:      node.DC,
+		Nid:     node.NID,
+		Service: node.Service,
+		Rpc: &ion.RPC{
+			Protocol: string(node.RPC.Protocol),
+			Addr:     node.RPC.Addr,
+			Params:   params,
+		},
 	}
 }
 
@@ -34,66 +97,406 @@ func newISLBServer(conf Config, in *ISLB, redis *db.Redis) *islbServer {
 // value = [...stream/track info ...]
 func (s *islbServer) PostISLBEvent(ctx context.Context, event *islb.ISLBEvent) (*ion.Empty, error) {
 	log.Infof("ISLBServer.PostISLBEvent")
+	s.handleEvent(s.conf.Global.Dc, event)
+	s.publish(event)
+	return &ion.Empty{}, nil
+}
+
+// handleEvent save the stream or session event of a node of dc to the store
+func (s *islbServer) handleEvent(dc string, event *islb.ISLBEvent) {
 	switch payload := event.Payload.(type) {
 	case *islb.ISLBEvent_Stream:
-		stream := payload.Stream
-		state := stream.State
-		mkey := s.conf.Global.Dc + "/" + stream.Nid + "/" + stream.Sid + "/" + stream.Uid
-		data, err := json.Marshal(stream.Streams)
-		if err != nil {
-			log.Errorf("json.Marshal err => %v", err)
+		s.handleStreamEvent(dc, payload.Stream)
+	case *islb.ISLBEvent_Session:
+		s.handleSessionEvent(dc, payload.Session)
+	}
+}
+
+func (s *islbServer) handleStreamEvent(dc string, stream *ion.StreamEvent) {
+	if stream.Origin != "" && stream.Origin != stream.Nid {
+		// a copy of the streams of another sfu of a cascaded session
+		return
+	}
+	state := stream.State
+	mkey := dc + "/" + stream.Nid + "/" + stream.Sid + "/" + stream.Uid
+
+	jstr, err := json.MarshalIndent(stream.Streams, "", "  ")
+	if err != nil {
+		log.Errorf("json.MarshalIndent failed %v", err)
+	}
+	log.Infof("ISLBEvent:\nmkey=> %v\nstate = %v\nstreams => %v", mkey, state.String(), string(jstr))
+
+	// the events only carry the changed streams and tracks
+	var streams []*ion.Stream
+	if value, ok := s.store.Get(mkey).(string); ok && value != "" {
+		if err := json.Unmarshal([]byte(value), &streams); err != nil {
+			log.Errorf("json.Unmarshal %v err => %v", mkey, err)
 		}
+	}
+	streams = util.ApplyStreamEvent(streams, state, stream.Streams)
 
-		jstr, err := json.MarshalIndent(stream.Streams, "", "  ")
+	if len(streams) == 0 {
+		err := s.store.Del(mkey)
 		if err != nil {
-			log.Errorf("json.MarshalIndent failed %v", err)
+			log.Errorf("s.Redis.Del failed %v", err)
+		}
+		return
+	}
+	data, err := json.Marshal(streams)
+	if err != nil {
+		log.Errorf("json.Marshal err => %v", err)
+		return
+	}
+	err = s.store.Set(mkey, string(data), redisLongKeyTTL)
+	if err != nil {
+		log.Errorf("s.Redis.Set failed %v", err)
+	}
+}
+
+// handleFederatedEvent apply an event posted to the islb of another dc,
+// or to another islb of this dc, which already saved it to the shared store.
+func (s *islbServer) handleFederatedEvent(dc string, event *islb.ISLBEvent) {
+	if dc != s.conf.Global.Dc {
+		s.handleEvent(dc, event)
+	}
+	s.broadcast(event)
+}
+
+// publish broadcast an event posted to this islb, and forward it to the other islbs,
+// followed by its copies for the other sfus of a cascaded session
+func (s *islbServer) publish(event *islb.ISLBEvent) {
+	s.publishEvent(event)
+	for _, relayed := range s.relayedEvents(event) {
+		s.publishEvent(relayed)
+	}
+}
+
+func (s *islbServer) publishEvent(event *islb.ISLBEvent) {
+	s.broadcast(event)
+	if s.federation != nil {
+		s.federation.publish(event)
+	}
+}
+
+// broadcast number event and queue it to the watchers subscribed to it, it never blocks on a watcher.
+func (s *islbServer) broadcast(event *islb.ISLBEvent) {
+	s.seqLock.Lock()
+	defer s.seqLock.Unlock()
+
+	s.seq++
+	event.Seq = s.seq
+	event.Islb = s.id
+	s.history = append(s.history, event)
+	if len(s.history) > replayBufferSize {
+		s.history = s.history[len(s.history)-replayBufferSize:]
+	}
+
+	s.watchLock.RLock()
+	defer s.watchLock.RUnlock()
+	for w := range s.watchers {
+		if w.match(event) {
+			w.send(event)
+		}
+	}
+}
+
+// subscribe extend the subscription of w with req, then queue the events after
+// req.ResumeAfter, or a snapshot of the current streams if they are not
+// buffered anymore. The snapshot is read without seqLock, the sfus posting
+// events never wait for it: the events numbered while it is read are queued
+// after it, an event already in the snapshot only repeats its state.
+func (s *islbServer) subscribe(w *watcher, req *islb.WatchRequest) {
+	s.seqLock.Lock()
+	if req.ResumeAfter > 0 && s.replay(w, req) {
+		w.subscribe(req)
+		s.seqLock.Unlock()
+		return
+	}
+	seq := s.seq
+	s.seqLock.Unlock()
+
+	for {
+		events := s.snapshot(req, seq)
+
+		s.seqLock.Lock()
+		missed, ok := s.since(req, seq)
+		if ok {
+			w.sendAll(append(events, missed...))
+			w.subscribe(req)
+			s.seqLock.Unlock()
+			return
 		}
-		log.Infof("ISLBEvent:\nmkey=> %v\nstate = %v\nstreams => %v", mkey, state.String(), string(jstr))
+		// more events than the history holds, read it again
+		seq = s.seq
+		s.seqLock.Unlock()
+	}
+}
 
-		switch state {
-		case ion.StreamEvent_ADD:
-			err := s.redis.Set(mkey, string(data), redisLongKeyTTL)
-			if err != nil {
-				log.Errorf("s.Redis.Set failed %v", err)
-			}
-		case ion.StreamEvent_REMOVE:
-			err := s.redis.Del(mkey)
-			if err != nil {
-				log.Errorf("s.Redis.Del failed %v", err)
+// replay queue the buffered events matching req after req.ResumeAfter,
+// return false if some of them are missing. s.seqLock is held.
+func (s *islbServer) replay(w *watcher, req *islb.WatchRequest) bool {
+	if req.ResumeIslb != s.id {
+		// numbered by another islb
+		return false
+	}
+	events, ok := s.since(req, req.ResumeAfter)
+	if !ok {
+		return false
+	}
+	w.sendAll(events)
+	return true
+}
+
+// since return the buffered events matching req numbered after seq, false if
+// some of them are missing. s.seqLock is held.
+func (s *islbServer) since(req *islb.WatchRequest, seq uint64) ([]*islb.ISLBEvent, bool) {
+	if seq > s.seq {
+		// numbered before a restart
+		return nil, false
+	}
+	if seq < s.seq && (len(s.history) == 0 || s.history[0].Seq > seq+1) {
+		return nil, false
+	}
+	var events []*islb.ISLBEvent
+	for _, event := range s.history {
+		if event.Seq <= seq {
+			continue
+		}
+		if nid, sid := eventTarget(event); requestMatch(req, nid, sid) {
+			events = append(events, event)
+		}
+	}
+	return events, true
+}
+
+// parseStreamKey split the key of a stream record, false for the other
+// records of the store
+// key = dc/nid/sid/uid
+func parseStreamKey(key string) ([]string, bool) {
+	for _, prefix := range []string{nodeKeyPrefix, sessionKeyPrefix, cascadeClaimPrefix, admitKeyPrefix} {
+		if strings.HasPrefix(key, prefix) {
+			return nil, false
+		}
+	}
+	strs := strings.Split(key, "/")
+	return strs, len(strs) >= 4
+}
+
+// snapshot return an ADD event for every session and stream matching req,
+// those of the other dcs included, numbered seq
+// key = dc/nid/sid/uid
+func (s *islbServer) snapshot(req *islb.WatchRequest, seq uint64) []*islb.ISLBEvent {
+	pattern := "*"
+	switch {
+	case req.Sid != "":
+		pattern = "*/*/" + db.EscapeGlob(req.Sid) + "/*"
+	case req.Nid != "":
+		pattern = "*/" + db.EscapeGlob(req.Nid) + "/*"
+	}
+
+	sid := db.EscapeGlob(req.Sid)
+	if sid == "" {
+		sid = "*"
+	}
+	var events []*islb.ISLBEvent
+	for _, info := range getSessions(s.store, "*", sid) {
+		if !requestMatch(req, info.NID, info.SID) {
+			continue
+		}
+		events = append(events, &islb.ISLBEvent{
+			Payload: &islb.ISLBEvent_Session{
+				Session: &ion.SessionEvent{
+					State: ion.SessionEvent_ADD,
+					Nid:   info.NID,
+					Sid:   info.SID,
+					Peers: uint32(info.Peers),
+				},
+			},
+			Seq:  seq,
+			Islb: s.id,
+		})
+	}
+
+	var keys []string
+	for _, key := range s.store.Keys(pattern) {
+		if strs, ok := parseStreamKey(key); ok && requestMatch(req, strs[1], strs[2]) {
+			keys = append(keys, key)
+		}
+	}
+	for i, value := range s.store.MGet(keys) {
+		key := keys[i]
+		strs := strings.Split(key, "/")
+		if value == "" {
+			continue
+		}
+		var streams []*ion.Stream
+		if err := json.Unmarshal([]byte(value), &streams); err != nil {
+			log.Errorf("json.Unmarshal %v err => %v", key, err)
+			continue
+		}
+		events = append(events, &islb.ISLBEvent{
+			Payload: &islb.ISLBEvent_Stream{
+				Stream: &ion.StreamEvent{
+					State:   ion.StreamEvent_ADD,
+					Nid:     strs[1],
+					Sid:     strs[2],
+					Uid:     strs[3],
+					Streams: streams,
+				},
+			},
+			Seq:  seq,
+			Islb: s.id,
+		})
+	}
+	return events
+}
+
+// handleNodeDown purge the streams of a sfu node that went away,
+// it will never send their REMOVE events if it crashed.
+func (s *islbServer) handleNodeDown(node discovery.Node) {
+	if node.Service != proto.ServiceSFU {
+		return
+	}
+	s.purgeNode(node)
+}
+
+// purgeNode delete the stream and session records of node, keyed under
+// the dc of its record, and broadcast a REMOVE event for each of them, the
+// streams first so their copies on the edges of a cascaded session are
+// removed too
+// key = dc/nid/sid/uid
+func (s *islbServer) purgeNode(node discovery.Node) {
+	s.purgeLock.Lock()
+	defer s.purgeLock.Unlock()
+
+	nid, dc := node.NID, node.DC
+	if dc == "" {
+		dc = s.conf.Global.Dc
+	}
+	s.purgeStreams(db.EscapeGlob(dc)+"/"+db.EscapeGlob(nid)+"/*", s.publish)
+
+	for _, info := range getSessions(s.store, db.EscapeGlob(dc), "*") {
+		if _, edge := info.Edges[nid]; edge {
+			if err := s.store.HDel(sessionKey(dc, info.SID), edgeField(nid)); err != nil {
+				log.Errorf("s.store.HDel failed %v", err)
 			}
+			continue
+		}
+		if info.NID != nid {
+			continue
 		}
+		if err := s.store.Del(sessionKey(dc, info.SID)); err != nil {
+			log.Errorf("s.store.Del failed %v", err)
+			continue
+		}
+		log.Infof("islb: purge stale session %v of node %v", info.SID, nid)
 
-		for _, wstream := range s.watchers {
-			err := wstream.Send(event)
-			if err != nil {
-				log.Errorf("wstream.Send(event): failed %v", err)
+		s.publish(&islb.ISLBEvent{
+			Payload: &islb.ISLBEvent_Session{
+				Session: &ion.SessionEvent{
+					State: ion.SessionEvent_REMOVE,
+					Nid:   nid,
+					Sid:   info.SID,
+				},
+			},
+		})
+	}
+}
+
+// purgeStreams delete the stream records matching pattern and send a
+// REMOVE event for each of them, s.purgeLock is held
+func (s *islbServer) purgeStreams(pattern string, send func(*islb.ISLBEvent)) {
+	keys := s.store.Keys(pattern)
+	for i, value := range s.store.MGet(keys) {
+		key := keys[i]
+		strs, ok := parseStreamKey(key)
+		if !ok {
+			continue
+		}
+
+		var streams []*ion.Stream
+		if value != "" {
+			if err := json.Unmarshal([]byte(value), &streams); err != nil {
+				log.Errorf("json.Unmarshal %v err => %v", key, err)
 			}
 		}
 
-	case *islb.ISLBEvent_Session:
-		//session := payload.Session
-		//log.Infof("ISLBEvent_Session event %v", session.String())
+		if err := s.store.Del(key); err != nil {
+			log.Errorf("s.store.Del failed %v", err)
+			continue
+		}
+		log.Infof("islb: purge stale streams %v", key)
+
+		send(&islb.ISLBEvent{
+			Payload: &islb.ISLBEvent_Stream{
+				Stream: &ion.StreamEvent{
+					State:   ion.StreamEvent_REMOVE,
+					Nid:     strs[1],
+					Sid:     strs[2],
+					Uid:     strs[3],
+					Streams: streams,
+				},
+			},
+		})
+	}
+}
+
+// purgeDC delete the stream and session records federated from dc, no islb
+// of dc is left to remove them. The REMOVE events are only broadcast, the
+// other islbs purge their own copies.
+func (s *islbServer) purgeDC(dc string) {
+	s.purgeLock.Lock()
+	defer s.purgeLock.Unlock()
+
+	s.purgeStreams(db.EscapeGlob(dc)+"/*", s.broadcast)
+
+	for _, info := range getSessions(s.store, db.EscapeGlob(dc), "*") {
+		if err := s.store.Del(sessionKey(dc, info.SID)); err != nil {
+			log.Errorf("s.store.Del failed %v", err)
+			continue
+		}
+		log.Infof("islb: purge stale session %v of dc %v", info.SID, dc)
+
+		s.broadcast(&islb.ISLBEvent{
+			Payload: &islb.ISLBEvent_Session{
+				Session: &ion.SessionEvent{
+					State: ion.SessionEvent_REMOVE,
+					Nid:   info.NID,
+					Sid:   info.SID,
+				},
+			},
+		})
 	}
-	return &ion.Empty{}, nil
 }
 
 //WatchISLBEvent broadcast ISLBEvent to ion-biz node.
 //The stream metadata is forwarded to biz node and coupled with the peer in the client through UID
+//Each WatchRequest adds a session (sid) or a node (nid) to the events watched by the stream,
+//and is answered with the current streams or the events missed since WatchRequest.ResumeAfter.
 func (s *islbServer) WatchISLBEvent(stream islb.ISLB_WatchISLBEventServer) error {
-	var sid string
+	w := newWatcher(stream)
+	s.watchLock.Lock()
+	s.watchers[w] = struct{}{}
+	s.watchLock.Unlock()
+
 	defer func() {
-		delete(s.watchers, sid)
+		s.watchLock.Lock()
+		delete(s.watchers, w)
+		s.watchLock.Unlock()
 	}()
-	for {
-		req, err := stream.Recv()
-		if err != nil {
-			log.Errorf("ISLBServer.WatchISLBEvent server stream.Recv() err: %v", err)
-			return err
-		}
-		log.Infof("ISLBServer.WatchISLBEvent req => %v", req)
-		sid = req.Sid
-		if _, found := s.watchers[sid]; !found {
-			s.watchers[sid] = stream
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				log.Errorf("ISLBServer.WatchISLBEvent server stream.Recv() err: %v", err)
+				w.close(err)
+				return
+			}
+			log.Infof("ISLBServer.WatchISLBEvent req => %v", req)
+			s.subscribe(w, req)
 		}
-	}
+	}()
+
+	return w.run()
 }
@@ -0,0 +1,90 @@
+package islb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
+	"github.com/pion/ion/pkg/db"
+	ion "github.com/pion/ion/proto/ion"
+	islb "github.com/pion/ion/proto/islb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay(t *testing.T) {
+	s := &islbServer{id: "islb-01/a", watchers: make(map[*watcher]struct{})}
+	for _, uid := range []string{"u1", "u2", "u3"} {
+		s.broadcast(&islb.ISLBEvent{
+			Payload: &islb.ISLBEvent_Stream{
+				Stream: &ion.StreamEvent{Nid: "sfu-01", Sid: "room1", Uid: uid},
+			},
+		})
+	}
+
+	w := newWatcher(nil)
+	assert.True(t, s.replay(w, &islb.WatchRequest{Sid: "room1", ResumeAfter: 1, ResumeIslb: "islb-01/a"}))
+	assert.Len(t, w.queue, 1)
+	events := <-w.queue
+	assert.Len(t, events, 2)
+	assert.Equal(t, "u2", events[0].GetStream().Uid)
+	assert.Equal(t, "islb-01/a", events[0].Islb)
+
+	// the same seq numbered by another islb, or by this one before a restart
+	w = newWatcher(nil)
+	assert.False(t, s.replay(w, &islb.WatchRequest{Sid: "room1", ResumeAfter: 1, ResumeIslb: "islb-02/b"}))
+	assert.False(t, s.replay(w, &islb.WatchRequest{Sid: "room1", ResumeAfter: 1}))
+	assert.Len(t, w.queue, 0)
+}
+
+func TestSubscribeSnapshot(t *testing.T) {
+	store := db.NewMemory()
+	s := &islbServer{store: store, id: "islb-01/a", watchers: make(map[*watcher]struct{})}
+	s.broadcast(&islb.ISLBEvent{Payload: &islb.ISLBEvent_Stream{Stream: &ion.StreamEvent{Nid: "sfu-01", Sid: "room0"}}})
+
+	// more records than the queue holds, and a reservation that is not one
+	for i := 0; i < 2*watcherQueueSize; i++ {
+		assert.NoError(t, store.Set(fmt.Sprintf("dc1/sfu-01/room%d/u1", i), "[]", 0))
+	}
+	assert.NoError(t, store.HSet(sessionKey("dc1", "room1"), "nid", "sfu-01"))
+	assert.NoError(t, store.Set(admitKeyPrefix+"dc1/sfu-01/1", "1", 0))
+
+	w := newWatcher(nil)
+	s.watchers[w] = struct{}{}
+	s.subscribe(w, &islb.WatchRequest{})
+	select {
+	case <-w.done:
+		t.Fatal("watcher disconnected by its snapshot")
+	default:
+	}
+	assert.Len(t, w.queue, 1)
+	events := <-w.queue
+	assert.Len(t, events, 1+2*watcherQueueSize)
+	assert.Equal(t, "room1", events[0].GetSession().Sid)
+	for _, event := range events {
+		assert.Equal(t, uint64(1), event.Seq)
+	}
+
+	// subscribed once its snapshot is queued
+	s.broadcast(&islb.ISLBEvent{Payload: &islb.ISLBEvent_Stream{Stream: &ion.StreamEvent{Nid: "sfu-02", Sid: "room9"}}})
+	events = <-w.queue
+	assert.Equal(t, uint64(2), events[0].Seq)
+}
+
+func TestPurgeNode(t *testing.T) {
+	store := db.NewMemory()
+	s := &islbServer{store: store, watchers: make(map[*watcher]struct{})}
+	s.conf.Global.Dc = "dc1"
+	for _, dc := range []string{"dc1", "dc2"} {
+		assert.NoError(t, store.Set(dc+"/sfu-09/room1/u1", "[]", 0))
+		assert.NoError(t, store.HSet(sessionKey(dc, "room1"), "nid", "sfu-09"))
+	}
+
+	// an sfu of dc2 expired, the records of dc1 are those of another node
+	s.purgeNode(discovery.Node{DC: "dc2", Service: "sfu", NID: "sfu-09"})
+	assert.Equal(t, []string{"dc1/sfu-09/room1/u1"}, store.Keys("*/sfu-09/*"))
+	_, found := getSession(store, "dc2", "room1")
+	assert.False(t, found)
+	_, found = getSession(store, "dc1", "room1")
+	assert.True(t, found)
+	assert.Len(t, s.history, 2)
+}
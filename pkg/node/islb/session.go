@@ -0,0 +1,142 @@
+package islb
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/pion/ion-log"
+	"github.com/pion/ion/pkg/db"
+	ion "github.com/pion/ion/proto/ion"
+)
+
+const (
+	// the live sessions are kept in the store as hashes under
+	// key = ion-session/dc/sid
+	// value = {nid: ${nid}, created: ${unix seconds}, peers: ${count}, edge:${nid}: ${count}...}
+	sessionKeyPrefix = "ion-session/"
+	// the sfus a cascaded session is relayed to, and their peer count
+	edgeFieldPrefix = "edge:"
+)
+
+// sessionInfo a row of the session table
+type sessionInfo struct {
+	SID     string
+	NID     string
+	Created time.Time
+	Peers   int
+	// Edges peer count by nid of the other sfus of a cascaded session
+	Edges map[string]int
+}
+
+func sessionKey(dc, sid string) string {
+	return sessionKeyPrefix + dc + "/" + sid
+}
+
+func edgeField(nid string) string {
+	return edgeFieldPrefix + nid
+}
+
+// nids return the sfus of the session, its host first
+func (info sessionInfo) nids() []string {
+	nids := []string{info.NID}
+	for nid := range info.Edges {
+		nids = append(nids, nid)
+	}
+	sort.Strings(nids[1:])
+	return nids
+}
+
+// getSession read the session sid from the table
+func getSession(store db.Store, dc, sid string) (sessionInfo, bool) {
+	fields := store.HGetAll(sessionKey(dc, sid))
+	if fields["nid"] == "" {
+		return sessionInfo{}, false
+	}
+	created, _ := strconv.ParseInt(fields["created"], 10, 64)
+	peers, _ := strconv.Atoi(fields["peers"])
+	info := sessionInfo{
+		SID:     sid,
+		NID:     fields["nid"],
+		Created: time.Unix(created, 0),
+		Peers:   peers,
+		Edges:   make(map[string]int),
+	}
+	for field, value := range fields {
+		if strings.HasPrefix(field, edgeFieldPrefix) {
+			info.Edges[strings.TrimPrefix(field, edgeFieldPrefix)], _ = strconv.Atoi(value)
+		}
+	}
+	return info, true
+}
+
+// getSessions read the sessions of the table matching the glob patterns dc
+// and sid, "*" for all of them
+func getSessions(store db.Store, dc, sid string) []sessionInfo {
+	var sessions []sessionInfo
+	for _, key := range store.Keys(sessionKey(dc, sid)) {
+		// key = ion-session/dc/sid
+		strs := strings.SplitN(strings.TrimPrefix(key, sessionKeyPrefix), "/", 2)
+		if len(strs) < 2 {
+			continue
+		}
+		if info, found := getSession(store, strs[0], strs[1]); found {
+			sessions = append(sessions, info)
+		}
+	}
+	return sessions
+}
+
+// handleSessionEvent update the session table from the events of the sfu nodes of dc
+func (s *islbServer) handleSessionEvent(dc string, event *ion.SessionEvent) {
+	key := sessionKey(dc, event.Sid)
+	log.Infof("ISLBEvent:\nsession => %v\nstate = %v\nnid = %v, peers = %v", event.Sid, event.State.String(), event.Nid, event.Peers)
+
+	// the edges of a cascaded session only count their peers
+	if info, found := getSession(s.store, dc, event.Sid); found && info.NID != event.Nid {
+		if _, edge := info.Edges[event.Nid]; edge {
+			s.handleEdgeEvent(dc, event)
+			return
+		}
+	}
+
+	switch event.State {
+	case ion.SessionEvent_ADD, ion.SessionEvent_UPDATE:
+		// keep the creation time of a session already known
+		if info, found := getSession(s.store, dc, event.Sid); !found || info.NID != event.Nid {
+			if err := s.store.HSetTTL(key, "created", time.Now().Unix(), redisLongKeyTTL); err != nil {
+				log.Errorf("s.store.HSetTTL failed %v", err)
+			}
+		}
+		if err := s.store.HSetTTL(key, "nid", event.Nid, redisLongKeyTTL); err != nil {
+			log.Errorf("s.store.HSetTTL failed %v", err)
+		}
+		if err := s.store.HSetTTL(key, "peers", event.Peers, redisLongKeyTTL); err != nil {
+			log.Errorf("s.store.HSetTTL failed %v", err)
+		}
+	case ion.SessionEvent_REMOVE:
+		// a session moved to another node is not removed by its old node
+		if info, found := getSession(s.store, dc, event.Sid); found && info.NID != event.Nid {
+			return
+		}
+		if err := s.store.Del(key); err != nil {
+			log.Errorf("s.store.Del failed %v", err)
+		}
+	}
+}
+
+func (s *islbServer) handleEdgeEvent(dc string, event *ion.SessionEvent) {
+	key := sessionKey(dc, event.Sid)
+	switch event.State {
+	case ion.SessionEvent_ADD, ion.SessionEvent_UPDATE:
+		if err := s.store.HSetTTL(key, edgeField(event.Nid), event.Peers, redisLongKeyTTL); err != nil {
+			log.Errorf("s.store.HSetTTL failed %v", err)
+		}
+	case ion.SessionEvent_REMOVE:
+		if err := s.store.HDel(key, edgeField(event.Nid)); err != nil {
+			log.Errorf("s.store.HDel failed %v", err)
+		}
+		go s.uncascadeSession(dc, event.Sid, event.Nid)
+	}
+}
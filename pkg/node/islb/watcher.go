@@ -0,0 +1,134 @@
+package islb
+
+import (
+	"sync"
+
+	log "github.com/pion/ion-log"
+	islb "github.com/pion/ion/proto/islb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// batches of events queued for a watcher before it is considered too slow
+	watcherQueueSize = 1024
+)
+
+// watcher is a WatchISLBEvent subscriber.
+// Every WatchRequest received on the stream extends the subscription:
+// a request with a sid subscribes to the session,
+// a request with only a nid subscribes to every session of the node,
+// an empty request subscribes to all the events.
+type watcher struct {
+	stream islb.ISLB_WatchISLBEventServer
+
+	mu   sync.RWMutex
+	sids map[string]bool
+	nids map[string]bool
+	all  bool
+
+	// a batch is a single event, or the whole snapshot or replay of a request
+	queue chan []*islb.ISLBEvent
+	done  chan struct{}
+	once  sync.Once
+	err   error
+}
+
+func newWatcher(stream islb.ISLB_WatchISLBEventServer) *watcher {
+	return &watcher{
+		stream: stream,
+		sids:   make(map[string]bool),
+		nids:   make(map[string]bool),
+		queue:  make(chan []*islb.ISLBEvent, watcherQueueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+func (w *watcher) subscribe(req *islb.WatchRequest) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case req.Sid != "":
+		w.sids[req.Sid] = true
+	case req.Nid != "":
+		w.nids[req.Nid] = true
+	default:
+		w.all = true
+	}
+}
+
+// match return true if the watcher subscribed to the session or node of event
+func (w *watcher) match(event *islb.ISLBEvent) bool {
+	nid, sid := eventTarget(event)
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.all || w.sids[sid] || w.nids[nid]
+}
+
+// eventTarget return the node and session event belongs to
+func eventTarget(event *islb.ISLBEvent) (nid, sid string) {
+	switch payload := event.Payload.(type) {
+	case *islb.ISLBEvent_Stream:
+		return payload.Stream.Nid, payload.Stream.Sid
+	case *islb.ISLBEvent_Session:
+		return payload.Session.Nid, payload.Session.Sid
+	}
+	return "", ""
+}
+
+// requestMatch return true if req alone subscribes to the node or session
+func requestMatch(req *islb.WatchRequest, nid, sid string) bool {
+	switch {
+	case req.Sid != "":
+		return req.Sid == sid
+	case req.Nid != "":
+		return req.Nid == nid
+	default:
+		return true
+	}
+}
+
+// send queue event without blocking, a watcher whose queue
+// is full is too slow to keep up and gets disconnected.
+func (w *watcher) send(event *islb.ISLBEvent) {
+	w.sendAll([]*islb.ISLBEvent{event})
+}
+
+// sendAll queue events as one batch, whatever their number
+func (w *watcher) sendAll(events []*islb.ISLBEvent) {
+	if len(events) == 0 {
+		return
+	}
+	select {
+	case w.queue <- events:
+	case <-w.done:
+	default:
+		log.Warnf("islb watcher too slow, %v events pending, disconnect it", len(w.queue))
+		w.close(status.Errorf(codes.ResourceExhausted, "too many pending events"))
+	}
+}
+
+// run send the queued events to the stream until the watcher is closed
+func (w *watcher) run() error {
+	for {
+		select {
+		case events := <-w.queue:
+			for _, event := range events {
+				if err := w.stream.Send(event); err != nil {
+					log.Errorf("wstream.Send(event): failed %v", err)
+					w.close(err)
+					break
+				}
+			}
+		case <-w.done:
+			return w.err
+		}
+	}
+}
+
+func (w *watcher) close(err error) {
+	w.once.Do(func() {
+		w.err = err
+		close(w.done)
+	})
+}
@@ -0,0 +1,135 @@
+package sfu
+
+import (
+	"context"
+
+	log "github.com/pion/ion-log"
+	"github.com/pion/ion-sfu/pkg/relay"
+	isfu "github.com/pion/ion-sfu/pkg/sfu"
+	"github.com/pion/ion/pkg/proto"
+	pb "github.com/pion/ion/proto/sfu"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Cascade is called by the islb when a session spans several sfus, req.Nids
+// are all the sfus of the session. The publishers of the session are relayed
+// to the sfus new to it, and the sfus gone from it are forgotten, so that a
+// later cascade to one of them relays the publishers again. A sfu without
+// peers of the session keeps the cascade for the peers to come, and answers
+// NotFound.
+func (s *sfuServer) Cascade(ctx context.Context, req *pb.CascadeRequest) (*pb.CascadeReply, error) {
+	want := make(map[string]bool)
+	for _, nid := range req.Nids {
+		if nid != s.sn.NID {
+			want[nid] = true
+		}
+	}
+
+	var added, removed []string
+	s.mu.Lock()
+	nids := s.cascades[req.Sid]
+	if nids == nil {
+		nids = make(map[string]bool)
+		s.cascades[req.Sid] = nids
+	}
+	for nid := range nids {
+		if !want[nid] {
+			delete(nids, nid)
+			removed = append(removed, nid)
+		}
+	}
+	for nid := range want {
+		if !nids[nid] {
+			nids[nid] = true
+			added = append(added, nid)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(removed) > 0 {
+		log.Infof("sfu: session %v no longer cascaded to %v", req.Sid, removed)
+	}
+	if len(added) == 0 {
+		return &pb.CascadeReply{}, nil
+	}
+	log.Infof("sfu: cascade session %v to %v", req.Sid, added)
+
+	// the peers joining later are relayed by relayPeer
+	session := s.localSession(req.Sid)
+	if session == nil {
+		return nil, status.Errorf(codes.NotFound, "session %v has no peer on %v", req.Sid, s.sn.NID)
+	}
+	for _, peer := range session.Peers() {
+		for _, nid := range added {
+			s.relay(peer, nid)
+		}
+	}
+	return &pb.CascadeReply{}, nil
+}
+
+// localSession return the session sid of the peers joined to the node, nil
+// if none is. Unlike sfu.GetSession, it never creates the session.
+func (s *sfuServer) localSession(sid string) isfu.Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.peers[sid] {
+		if session := p.peer.Session(); session != nil {
+			return session
+		}
+	}
+	return nil
+}
+
+// Relay answer the relay offer of a publisher of another sfu
+func (s *sfuServer) Relay(ctx context.Context, req *pb.RelayRequest) (*pb.RelayReply, error) {
+	session, _ := s.sfu.GetSession(req.Sid)
+	signal, err := session.AddRelayPeer(req.Pid, req.Signal)
+	if err != nil {
+		log.Errorf("session.AddRelayPeer(%v) err => %v", req.Pid, err)
+		return nil, status.Errorf(codes.Internal, "relay peer %v: %v", req.Pid, err)
+	}
+	return &pb.RelayReply{Signal: signal}, nil
+}
+
+// relayPeer relay a peer joining a cascaded session to the other sfus
+func (s *sfuServer) relayPeer(peer isfu.Peer) {
+	sid := peer.Session().ID()
+	s.mu.Lock()
+	var nids []string
+	for nid := range s.cascades[sid] {
+		nids = append(nids, nid)
+	}
+	s.mu.Unlock()
+
+	for _, nid := range nids {
+		s.relay(peer, nid)
+	}
+}
+
+// relay the tracks published by peer to the sfu nid
+func (s *sfuServer) relay(peer isfu.Peer, nid string) {
+	publisher := peer.Publisher()
+	if publisher == nil {
+		return
+	}
+	_, err := publisher.Relay(func(meta relay.PeerMeta, signal []byte) ([]byte, error) {
+		cli, err := s.sn.NewNatsRPCClient(proto.ServiceSFU, nid, map[string]interface{}{})
+		if err != nil {
+			return nil, err
+		}
+		defer s.sn.Release(cli)
+		reply, err := pb.NewSFUClient(cli).Relay(context.Background(), &pb.RelayRequest{
+			Sid:    meta.SessionID,
+			Pid:    meta.PeerID,
+			Signal: signal,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return reply.Signal, nil
+	})
+	if err != nil {
+		log.Errorf("sfu: relay %v to %v err => %v", peer.ID(), nid, err)
+	}
+}
@@ -0,0 +1,190 @@
+package sfu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/pion/ion-log"
+	isfu "github.com/pion/ion-sfu/pkg/sfu"
+)
+
+// The keys of JoinRequest.config recognised by the sfu node,
+// the booleans are "true" or "false".
+const (
+	// configNoPublish join without a publisher transport, for viewers
+	configNoPublish = "NoPublish"
+	// configNoSubscribe join without a subscriber transport
+	configNoSubscribe = "NoSubscribe"
+	// configNoAutoSubscribe do not subscribe to the tracks of the session on join
+	configNoAutoSubscribe = "NoAutoSubscribe"
+	// configMaxBitrate cap in kbps of every track the peer publishes
+	configMaxBitrate = "MaxBitrate"
+	// configCodecs the codecs the peer should publish with, by preference,
+	// comma separated mime subtypes like "VP8,opus"
+	configCodecs = "Codecs"
+)
+
+// joinConfig the per peer settings of a JoinRequest
+type joinConfig struct {
+	NoPublish       bool
+	NoSubscribe     bool
+	NoAutoSubscribe bool
+	// MaxBitrate in kbps, 0 for no cap
+	MaxBitrate int
+	Codecs     []string
+}
+
+// parseJoinConfig read the recognised keys of config, the others are ignored
+func parseJoinConfig(config map[string]string) (joinConfig, error) {
+	var c joinConfig
+	for key, value := range config {
+		var err error
+		switch key {
+		case configNoPublish:
+			c.NoPublish, err = strconv.ParseBool(value)
+		case configNoSubscribe:
+			c.NoSubscribe, err = strconv.ParseBool(value)
+		case configNoAutoSubscribe:
+			c.NoAutoSubscribe, err = strconv.ParseBool(value)
+		case configMaxBitrate:
+			c.MaxBitrate, err = strconv.Atoi(value)
+			if err == nil && c.MaxBitrate < 0 {
+				err = fmt.Errorf("negative bitrate")
+			}
+		case configCodecs:
+			for _, codec := range strings.Split(value, ",") {
+				if codec = strings.TrimSpace(codec); codec != "" {
+					c.Codecs = append(c.Codecs, codec)
+				}
+			}
+		default:
+			log.Debugf("sfu: unknown join config %v = %v", key, value)
+		}
+		if err != nil {
+			return joinConfig{}, fmt.Errorf("invalid join config %v = %q: %w", key, value, err)
+		}
+	}
+	if c.NoPublish && c.NoSubscribe {
+		return joinConfig{}, fmt.Errorf("invalid join config: %v and %v", configNoPublish, configNoSubscribe)
+	}
+	return c, nil
+}
+
+// peerConfig the settings applied by the peer itself
+func (c joinConfig) peerConfig() isfu.JoinConfig {
+	return isfu.JoinConfig{
+		NoPublish:       c.NoPublish,
+		NoSubscribe:     c.NoSubscribe,
+		NoAutoSubscribe: c.NoAutoSubscribe,
+	}
+}
+
+// applyAnswer apply the bitrate cap and the codec preference to the
+// publisher answer, the browser sends with the first codec of each
+// media section and within its bandwidth line.
+func (c joinConfig) applyAnswer(sdp string) string {
+	if len(c.Codecs) > 0 {
+		sdp = preferCodecs(sdp, c.Codecs)
+	}
+	if c.MaxBitrate > 0 {
+		sdp = capBitrate(sdp, c.MaxBitrate)
+	}
+	return sdp
+}
+
+// sdpSections split sdp in its session section followed by its media sections
+func sdpSections(sdp string) [][]string {
+	lines := strings.Split(strings.TrimRight(sdp, "\r\n"), "\n")
+	sections := [][]string{nil}
+	for _, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.HasPrefix(line, "m=") {
+			sections = append(sections, nil)
+		}
+		sections[len(sections)-1] = append(sections[len(sections)-1], line)
+	}
+	return sections
+}
+
+func joinSDP(sections [][]string) string {
+	var b strings.Builder
+	for _, section := range sections {
+		for _, line := range section {
+			b.WriteString(line)
+			b.WriteString("\r\n")
+		}
+	}
+	return b.String()
+}
+
+// capBitrate set the bandwidth of the audio and video sections of sdp to kbps
+func capBitrate(sdp string, kbps int) string {
+	sections := sdpSections(sdp)
+	for i, section := range sections[1:] {
+		if !strings.HasPrefix(section[0], "m=audio") && !strings.HasPrefix(section[0], "m=video") {
+			continue
+		}
+		// b= goes after the m=, i= and c= lines
+		var lines []string
+		at := 1
+		for j, line := range section {
+			if strings.HasPrefix(line, "b=") {
+				continue
+			}
+			if j > 0 && (strings.HasPrefix(line, "i=") || strings.HasPrefix(line, "c=")) {
+				at = len(lines) + 1
+			}
+			lines = append(lines, line)
+		}
+		lines = append(lines[:at], append([]string{
+			"b=AS:" + strconv.Itoa(kbps),
+			"b=TIAS:" + strconv.Itoa(kbps*1000),
+		}, lines[at:]...)...)
+		sections[i+1] = lines
+	}
+	return joinSDP(sections)
+}
+
+// preferCodecs reorder the payload types of every media section of sdp,
+// the codecs first by preference, then the others in their order.
+func preferCodecs(sdp string, codecs []string) string {
+	sections := sdpSections(sdp)
+	for _, section := range sections[1:] {
+		// m=<media> <port> <proto> <fmt> ...
+		fields := strings.Fields(section[0])
+		if len(fields) < 4 {
+			continue
+		}
+		names := make(map[string]string)
+		for _, line := range section[1:] {
+			// a=rtpmap:<payload type> <encoding name>/<clock rate>[/<channels>]
+			if !strings.HasPrefix(line, "a=rtpmap:") {
+				continue
+			}
+			strs := strings.Fields(strings.TrimPrefix(line, "a=rtpmap:"))
+			if len(strs) == 2 {
+				names[strs[0]] = strings.ToLower(strings.Split(strs[1], "/")[0])
+			}
+		}
+
+		formats := fields[3:]
+		var ordered []string
+		taken := make(map[string]bool)
+		for _, codec := range codecs {
+			for _, pt := range formats {
+				if !taken[pt] && names[pt] == strings.ToLower(codec) {
+					ordered = append(ordered, pt)
+					taken[pt] = true
+				}
+			}
+		}
+		for _, pt := range formats {
+			if !taken[pt] {
+				ordered = append(ordered, pt)
+			}
+		}
+		section[0] = strings.Join(append(fields[:3], ordered...), " ")
+	}
+	return joinSDP(sections)
+}
@@ -0,0 +1,97 @@
+package sfu
+
+import (
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+const testAnswer = "v=0\r\n" +
+	"o=- 1 1 IN IP4 0.0.0.0\r\n" +
+	"s=-\r\n" +
+	"t=0 0\r\n" +
+	"m=audio 9 UDP/TLS/RTP/SAVPF 111 9\r\n" +
+	"c=IN IP4 0.0.0.0\r\n" +
+	"a=rtpmap:111 opus/48000/2\r\n" +
+	"a=rtpmap:9 G722/8000\r\n" +
+	"m=video 9 UDP/TLS/RTP/SAVPF 96 97 98\r\n" +
+	"c=IN IP4 0.0.0.0\r\n" +
+	"b=AS:2000\r\n" +
+	"a=rtpmap:96 VP8/90000\r\n" +
+	"a=rtpmap:97 rtx/90000\r\n" +
+	"a=fmtp:97 apt=96\r\n" +
+	"a=rtpmap:98 H264/90000\r\n" +
+	"m=application 9 UDP/DTLS/SCTP webrtc-datachannel\r\n" +
+	"c=IN IP4 0.0.0.0\r\n"
+
+func TestParseJoinConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   joinConfig
+		err    bool
+	}{
+		{name: "empty", config: nil},
+		{
+			name: "viewer",
+			config: map[string]string{
+				configNoPublish:       "true",
+				configNoAutoSubscribe: "false",
+				"unknown":             "ignored",
+			},
+			want: joinConfig{NoPublish: true},
+		},
+		{
+			name: "mobile",
+			config: map[string]string{
+				configMaxBitrate: "500",
+				configCodecs:     "H264, opus,",
+			},
+			want: joinConfig{MaxBitrate: 500, Codecs: []string{"H264", "opus"}},
+		},
+		{name: "bad bool", config: map[string]string{configNoSubscribe: "yes"}, err: true},
+		{name: "bad bitrate", config: map[string]string{configMaxBitrate: "-1"}, err: true},
+		{
+			name:   "no transport",
+			config: map[string]string{configNoPublish: "true", configNoSubscribe: "true"},
+			err:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := parseJoinConfig(tt.config)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, c)
+		})
+	}
+}
+
+func TestApplyAnswer(t *testing.T) {
+	assert.Equal(t, testAnswer, joinConfig{}.applyAnswer(testAnswer))
+
+	sdp := joinConfig{Codecs: []string{"h264", "G722"}, MaxBitrate: 300}.applyAnswer(testAnswer)
+	assert.Equal(t, "v=0\r\n"+
+		"o=- 1 1 IN IP4 0.0.0.0\r\n"+
+		"s=-\r\n"+
+		"t=0 0\r\n"+
+		"m=audio 9 UDP/TLS/RTP/SAVPF 9 111\r\n"+
+		"c=IN IP4 0.0.0.0\r\n"+
+		"b=AS:300\r\n"+
+		"b=TIAS:300000\r\n"+
+		"a=rtpmap:111 opus/48000/2\r\n"+
+		"a=rtpmap:9 G722/8000\r\n"+
+		"m=video 9 UDP/TLS/RTP/SAVPF 98 96 97\r\n"+
+		"c=IN IP4 0.0.0.0\r\n"+
+		"b=AS:300\r\n"+
+		"b=TIAS:300000\r\n"+
+		"a=rtpmap:96 VP8/90000\r\n"+
+		"a=rtpmap:97 rtx/90000\r\n"+
+		"a=fmtp:97 apt=96\r\n"+
+		"a=rtpmap:98 H264/90000\r\n"+
+		"m=application 9 UDP/DTLS/SCTP webrtc-datachannel\r\n"+
+		"c=IN IP4 0.0.0.0\r\n", sdp)
+}
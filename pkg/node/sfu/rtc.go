@@ -0,0 +1,282 @@
+package sfu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	log "github.com/pion/ion-log"
+	isfu "github.com/pion/ion-sfu/pkg/sfu"
+	"github.com/pion/ion/pkg/util"
+	"github.com/pion/ion/proto/ion"
+	"github.com/pion/ion/proto/rtc"
+	"github.com/pion/webrtc/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rtcServer serve the rtc.RTC signalling on top of the sfu of sfuServer.
+// Unlike SFU.Signal, the descriptions are raw sdp whose type follows from
+// their target: the client offers to the PUBLISHER and answers the offers of
+// the SUBSCRIBER, and the failures are reported as coded rtc.Error.
+type rtcServer struct {
+	rtc.UnimplementedRTCServer
+	s *sfuServer
+}
+
+func newRTCServer(s *sfuServer) *rtcServer {
+	return &rtcServer{s: s}
+}
+
+// maxPendingTrickles the candidates kept for a peer not joined yet, a
+// browser gathers a few per transport
+const maxPendingTrickles = 64
+
+// trickled a candidate received before the peer joined
+type trickled struct {
+	candidate webrtc.ICECandidateInit
+	target    rtc.Target
+}
+
+// rtcError a signalling error, the codes are those of grpc
+func rtcError(code codes.Code, format string, a ...interface{}) *rtc.Signalling {
+	return &rtc.Signalling{
+		Payload: &rtc.Signalling_Error{
+			Error: &rtc.Error{
+				Code:   int32(code),
+				Reason: fmt.Sprintf(format, a...),
+			},
+		},
+	}
+}
+
+// rtcErrorCode the code reporting err of the sfu peer
+func rtcErrorCode(err error) codes.Code {
+	switch err {
+	case isfu.ErrTransportExists, isfu.ErrNoTransportEstablished, isfu.ErrOfferIgnored:
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+func joinReply(err error) *rtc.Signalling {
+	reply := &rtc.JoinReply{Success: err == nil}
+	if err != nil {
+		reply.Error = err.Error()
+	}
+	return &rtc.Signalling{
+		Payload: &rtc.Signalling_Join{
+			Join: &rtc.Join{
+				Payload: &rtc.Join_Reply{Reply: reply},
+			},
+		},
+	}
+}
+
+func (r *rtcServer) Signal(stream rtc.RTC_SignalServer) error {
+	s := r.s
+	s.sn.StreamStarted()
+	defer s.sn.StreamEnded()
+
+	peer := isfu.NewPeer(s.sfu)
+	stats := newPeerState(peer)
+	var streams []*ion.Stream
+	// the metadata of the streams, set by the peer with or before them
+	metadata := make(map[string]map[string]string)
+	var pending []trickled
+	var config joinConfig
+	joined := ""
+
+	// the peer callbacks send from the transports goroutines
+	var mu sync.Mutex
+	send := func(sig *rtc.Signalling) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return stream.Send(sig)
+	}
+
+	defer func() {
+		peer.Close()
+		if peer.Session() != nil {
+			s.postStreamEvent(peer, ion.StreamEvent_REMOVE, streams)
+		}
+		if joined != "" {
+			s.removePeerState(joined, stats)
+			s.updateSession(joined, -1)
+		}
+	}()
+
+	for {
+		in, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			if errStatus, _ := status.FromError(err); errStatus.Code() == codes.Canceled {
+				return nil
+			}
+			log.Errorf("rtc signal error %v", err)
+			return err
+		}
+
+		var reply *rtc.Signalling
+		switch payload := in.Payload.(type) {
+		case *rtc.Signalling_Join:
+			req := payload.Join.GetReq()
+			switch {
+			case req == nil:
+				reply = rtcError(codes.InvalidArgument, "join without request")
+			case joined != "":
+				reply = rtcError(codes.FailedPrecondition, "already joined %v", joined)
+			default:
+				peer.OnICEConnectionStateChange = stats.setICEState
+				err := r.join(peer, req, &config, send)
+				reply = joinReply(err)
+				if err == nil {
+					joined = req.Sid
+					s.addPeerState(joined, stats)
+					s.updateSession(joined, 1)
+					s.relayPeer(peer)
+					trickle(peer, pending)
+					pending = nil
+				}
+			}
+
+		case *rtc.Signalling_Description:
+			desc := payload.Description
+			if joined == "" {
+				reply = rtcError(codes.FailedPrecondition, "description before join")
+				break
+			}
+			switch desc.Target {
+			case rtc.Target_PUBLISHER:
+				offer := webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: string(desc.Description)}
+				answer, err := peer.Answer(offer)
+				if err != nil {
+					reply = rtcError(rtcErrorCode(err), "publisher answer: %v", err)
+					break
+				}
+				reply = &rtc.Signalling{
+					Payload: &rtc.Signalling_Description{
+						Description: &rtc.Description{
+							Id:          desc.Id,
+							Target:      rtc.Target_PUBLISHER,
+							Description: []byte(config.applyAnswer(answer.SDP)),
+						},
+					},
+				}
+
+				newStreams, err := util.ParseSDP(offer.SDP)
+				if err != nil {
+					log.Errorf("util.ParseSDP error: %v", err)
+				} else {
+					newStreams = withMetadata(newStreams, metadata)
+					s.postStreamChanges(peer, streams, newStreams)
+					streams = newStreams
+				}
+			case rtc.Target_SUBSCRIBER:
+				answer := webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: string(desc.Description)}
+				if err := peer.SetRemoteDescription(answer); err != nil {
+					reply = rtcError(rtcErrorCode(err), "subscriber answer: %v", err)
+				}
+			default:
+				reply = rtcError(codes.InvalidArgument, "unknown target %v", desc.Target)
+			}
+
+		case *rtc.Signalling_Trickle:
+			var candidate webrtc.ICECandidateInit
+			if err := json.Unmarshal(payload.Trickle.Candidate, &candidate); err != nil {
+				reply = rtcError(codes.InvalidArgument, "unmarshal ice candidate: %v", err)
+				break
+			}
+			if joined == "" {
+				// the candidates may arrive before join, keep them
+				if len(pending) >= maxPendingTrickles {
+					reply = rtcError(codes.ResourceExhausted, "more than %v candidates before join", maxPendingTrickles)
+					break
+				}
+				pending = append(pending, trickled{candidate: candidate, target: payload.Trickle.Target})
+				break
+			}
+			if err := peer.Trickle(candidate, int(payload.Trickle.Target)); err != nil {
+				reply = rtcError(rtcErrorCode(err), "trickle: %v", err)
+			}
+
+		case *rtc.Signalling_Metadata:
+			if payload.Metadata.StreamId == "" {
+				reply = rtcError(codes.InvalidArgument, "metadata without stream id")
+				break
+			}
+			streams = s.setMetadata(peer, streams, metadata, payload.Metadata.StreamId, payload.Metadata.Metadata)
+
+		default:
+			reply = rtcError(codes.InvalidArgument, "unexpected signalling %T", in.Payload)
+		}
+
+		if reply != nil {
+			if err := send(reply); err != nil {
+				log.Errorf("rtc send error: %v", err)
+				return status.Errorf(codes.Internal, err.Error())
+			}
+		}
+	}
+}
+
+// join the peer to the session of req with the settings of its parameters
+func (r *rtcServer) join(peer *isfu.PeerLocal, req *rtc.JoinRequest, config *joinConfig, send func(*rtc.Signalling) error) error {
+	c, err := parseJoinConfig(req.Parameters)
+	if err != nil {
+		return err
+	}
+	*config = c
+
+	peer.OnIceCandidate = func(candidate *webrtc.ICECandidateInit, target int) {
+		bytes, err := json.Marshal(candidate)
+		if err != nil {
+			log.Errorf("OnIceCandidate error: %v", err)
+			return
+		}
+		err = send(&rtc.Signalling{
+			Payload: &rtc.Signalling_Trickle{
+				Trickle: &rtc.Trickle{
+					Target:    rtc.Target(target),
+					Candidate: bytes,
+				},
+			},
+		})
+		if err != nil {
+			log.Errorf("OnIceCandidate send error: %v", err)
+		}
+	}
+
+	peer.OnOffer = func(offer *webrtc.SessionDescription) {
+		err := send(&rtc.Signalling{
+			Payload: &rtc.Signalling_Description{
+				Description: &rtc.Description{
+					Target:      rtc.Target_SUBSCRIBER,
+					Description: []byte(offer.SDP),
+				},
+			},
+		})
+		if err != nil {
+			log.Errorf("negotiation error: %v", err)
+		}
+	}
+
+	if err := peer.Join(req.Sid, req.Uid, config.peerConfig()); err != nil {
+		log.Errorf("rtc join %v error: %v", req.Sid, err)
+		return err
+	}
+	return nil
+}
+
+// trickle the candidates received before join
+func trickle(peer *isfu.PeerLocal, pending []trickled) {
+	for _, t := range pending {
+		if err := peer.Trickle(t.candidate, int(t.target)); err != nil {
+			log.Warnf("peer trickle error: %v", err)
+		}
+	}
+}
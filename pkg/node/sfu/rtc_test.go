@@ -0,0 +1,213 @@
+package sfu
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pion/ion-sfu/pkg/buffer"
+	isfu "github.com/pion/ion-sfu/pkg/sfu"
+	"github.com/pion/ion/proto/ion"
+	"github.com/pion/ion/proto/rtc"
+	"github.com/pion/webrtc/v3"
+	"github.com/tj/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// signalStream a rtc.RTC_SignalServer reading in, the replies go to out
+type signalStream struct {
+	grpc.ServerStream
+	in  chan *rtc.Signalling
+	out chan *rtc.Signalling
+}
+
+func (s *signalStream) Recv() (*rtc.Signalling, error) {
+	sig, ok := <-s.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return sig, nil
+}
+
+func (s *signalStream) Send(sig *rtc.Signalling) error {
+	s.out <- sig
+	return nil
+}
+
+// startSignal run rtcServer.Signal on a new stream, the islb events go to posted
+func startSignal(posted *postedEvents) (*signalStream, chan error) {
+	c := conf.Config
+	c.BufferFactory = buffer.NewBufferFactory(500, isfu.Logger)
+	s := newSFUServer(NewSFU(nid), isfu.NewSFU(c), conf)
+	s.islbcli = posted
+
+	stream := &signalStream{
+		in:  make(chan *rtc.Signalling),
+		out: make(chan *rtc.Signalling, 256),
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- newRTCServer(s).Signal(stream)
+	}()
+	return stream, done
+}
+
+// reply the next answer to the client, the candidates and the offers of
+// the subscriber sent on their own are skipped
+func reply(t *testing.T, stream *signalStream) *rtc.Signalling {
+	for {
+		select {
+		case sig := <-stream.out:
+			if sig.GetTrickle() != nil || sig.GetDescription().GetTarget() == rtc.Target_SUBSCRIBER {
+				continue
+			}
+			return sig
+		case <-time.After(5 * time.Second):
+			t.Fatal("no reply")
+			return nil
+		}
+	}
+}
+
+func errorCode(sig *rtc.Signalling) codes.Code {
+	if sig.GetError() == nil {
+		return codes.OK
+	}
+	return codes.Code(sig.GetError().Code)
+}
+
+func joinSignal(sid, uid string) *rtc.Signalling {
+	return &rtc.Signalling{
+		Payload: &rtc.Signalling_Join{
+			Join: &rtc.Join{
+				Payload: &rtc.Join_Req{Req: &rtc.JoinRequest{Sid: sid, Uid: uid}},
+			},
+		},
+	}
+}
+
+func trickleSignal(candidate []byte) *rtc.Signalling {
+	return &rtc.Signalling{
+		Payload: &rtc.Signalling_Trickle{
+			Trickle: &rtc.Trickle{Target: rtc.Target_PUBLISHER, Candidate: candidate},
+		},
+	}
+}
+
+func descriptionSignal(target rtc.Target, sdp string) *rtc.Signalling {
+	return &rtc.Signalling{
+		Payload: &rtc.Signalling_Description{
+			Description: &rtc.Description{Id: "1", Target: target, Description: []byte(sdp)},
+		},
+	}
+}
+
+func TestRTCSignalErrors(t *testing.T) {
+	posted := &postedEvents{}
+	stream, done := startSignal(posted)
+
+	candidate, err := json.Marshal(webrtc.ICECandidateInit{Candidate: "candidate:1 1 udp 2122260223 192.168.1.2 50000 typ host"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		in   *rtc.Signalling
+		code codes.Code
+	}{
+		{
+			name: "description before join",
+			in:   descriptionSignal(rtc.Target_PUBLISHER, "v=0"),
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "join without request",
+			in:   &rtc.Signalling{Payload: &rtc.Signalling_Join{Join: &rtc.Join{}}},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "bad candidate",
+			in:   trickleSignal([]byte("{")),
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "metadata without stream id",
+			in:   &rtc.Signalling{Payload: &rtc.Signalling_Metadata{Metadata: &rtc.StreamMetadata{}}},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "no payload",
+			in:   &rtc.Signalling{},
+			code: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		stream.in <- tt.in
+		assert.Equal(t, tt.code, errorCode(reply(t, stream)), tt.name)
+	}
+
+	// the candidates before join are kept up to the cap
+	for i := 0; i < maxPendingTrickles; i++ {
+		stream.in <- trickleSignal(candidate)
+	}
+	stream.in <- trickleSignal(candidate)
+	assert.Equal(t, codes.ResourceExhausted, errorCode(reply(t, stream)))
+
+	stream.in <- joinSignal("room", "alice")
+	join := reply(t, stream).GetJoin().GetReply()
+	assert.True(t, join.GetSuccess(), join.GetError())
+
+	stream.in <- joinSignal("room", "alice")
+	assert.Equal(t, codes.FailedPrecondition, errorCode(reply(t, stream)))
+	stream.in <- descriptionSignal(rtc.Target(42), "v=0")
+	assert.Equal(t, codes.InvalidArgument, errorCode(reply(t, stream)))
+	stream.in <- descriptionSignal(rtc.Target_PUBLISHER, "hello")
+	assert.Equal(t, codes.Internal, errorCode(reply(t, stream)))
+
+	close(stream.in)
+	assert.NoError(t, <-done)
+
+	posted.mu.Lock()
+	defer posted.mu.Unlock()
+	assert.Len(t, posted.events, 2)
+	assert.Equal(t, ion.SessionEvent_ADD, posted.events[0].State)
+	assert.Equal(t, ion.SessionEvent_REMOVE, posted.events[1].State)
+}
+
+func TestRTCSignalPublisher(t *testing.T) {
+	posted := &postedEvents{}
+	stream, done := startSignal(posted)
+
+	stream.in <- joinSignal("room", "alice")
+	assert.True(t, reply(t, stream).GetJoin().GetReply().GetSuccess())
+
+	pub, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+	defer pub.Close()
+	track, err := webrtc.NewTrackLocalStaticSample(webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus}, "audio", "pion")
+	assert.NoError(t, err)
+	_, err = pub.AddTrack(track)
+	assert.NoError(t, err)
+	offer, err := pub.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, pub.SetLocalDescription(offer))
+
+	stream.in <- descriptionSignal(rtc.Target_PUBLISHER, offer.SDP)
+	answer := reply(t, stream).GetDescription()
+	assert.NotNil(t, answer)
+	assert.Equal(t, "1", answer.Id)
+	assert.Equal(t, rtc.Target_PUBLISHER, answer.Target)
+	assert.NoError(t, pub.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: string(answer.Description)}))
+
+	close(stream.in)
+	assert.NoError(t, <-done)
+
+	posted.mu.Lock()
+	defer posted.mu.Unlock()
+	assert.Len(t, posted.streams, 2)
+	assert.Equal(t, ion.StreamEvent_ADD, posted.streams[0].State)
+	assert.Equal(t, "alice", posted.streams[0].Uid)
+	assert.Equal(t, "pion", posted.streams[0].Streams[0].Id)
+	assert.Equal(t, ion.StreamEvent_REMOVE, posted.streams[1].State)
+}
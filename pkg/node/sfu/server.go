@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sync"
 
+	nrpc "github.com/cloudwebrtc/nats-grpc/pkg/rpc"
 	log "github.com/pion/ion-log"
 	isfu "github.com/pion/ion-sfu/pkg/sfu"
+	ionnode "github.com/pion/ion/pkg/ion"
 	"github.com/pion/ion/pkg/proto"
 	"github.com/pion/ion/pkg/util"
 	"github.com/pion/ion/proto/ion"
@@ -20,52 +23,249 @@ import (
 
 type sfuServer struct {
 	pb.UnimplementedSFUServer
-	sfu     *isfu.SFU
-	islbcli islb.ISLBClient
-	sn      *SFU
+	sfu  *isfu.SFU
+	sn   *SFU
+	conf Config
+
+	islbLock sync.Mutex
+	islbcli  islb.ISLBClient
+	// the pooled client of islbcli, released when the islb fails or the server closes
+	islbconn *nrpc.Client
+
+	mu sync.Mutex
+	// peer count by session id
+	sessions map[string]int
+	// the other sfus of the cascaded sessions, by session id
+	cascades map[string]map[string]bool
+	// the peers joined to the node, by session id and peer id
+	peers map[string]map[string]*peerState
+	// the session events being posted, by session id
+	posting map[string]*sessionPost
+	// kbps received and sent by the peers, at the last measure
+	bandwidth int
+
+	closeOnce sync.Once
+	done      chan struct{}
 }
 
-func newSFUServer(sn *SFU, sfu *isfu.SFU) *sfuServer {
-	return &sfuServer{sn: sn, sfu: sfu}
+// sessionPost serialize the session events of a session, an event is
+// counted and posted before the next one is counted, so the islb gets
+// them in order.
+type sessionPost struct {
+	mu   sync.Mutex
+	refs int
 }
 
-func (s *sfuServer) postISLBEvent(event *islb.ISLBEvent) {
+func newSFUServer(sn *SFU, sfu *isfu.SFU, conf Config) *sfuServer {
+	return &sfuServer{
+		sn:       sn,
+		sfu:      sfu,
+		conf:     conf,
+		sessions: make(map[string]int),
+		cascades: make(map[string]map[string]bool),
+		peers:    make(map[string]map[string]*peerState),
+		posting:  make(map[string]*sessionPost),
+		done:     make(chan struct{}),
+	}
+}
+
+func (s *sfuServer) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.islbLock.Lock()
+		cli := s.islbcli
+		s.islbLock.Unlock()
+		s.releaseISLBClient(cli)
+	})
+}
+
+// updateLoad refresh the load figures published by the node, s.mu is held
+func (s *sfuServer) updateLoad() {
+	peers := 0
+	for _, cnt := range s.sessions {
+		peers += cnt
+	}
+	s.sn.UpdateLoad(ionnode.Load{
+		Sessions:  len(s.sessions),
+		Peers:     peers,
+		Bandwidth: s.bandwidth,
+	})
+}
+
+// updateSession count a peer in (delta = 1) or out (delta = -1) of a session,
+// refresh the load figures published by the node, and tell the islb
+// when the session starts with its first peer or ends with its last one.
+func (s *sfuServer) updateSession(sid string, delta int) {
+	post := s.lockSessionPost(sid)
+	defer s.unlockSessionPost(sid, post)
+
+	s.mu.Lock()
+	s.sessions[sid] += delta
+	count := s.sessions[sid]
+	if count <= 0 {
+		delete(s.sessions, sid)
+		delete(s.cascades, sid)
+	}
+	s.updateLoad()
+	s.mu.Unlock()
+
+	state := ion.SessionEvent_UPDATE
+	if count <= 0 {
+		state, count = ion.SessionEvent_REMOVE, 0
+	} else if count == 1 && delta > 0 {
+		state = ion.SessionEvent_ADD
+	}
+	s.postISLBEvent(&islb.ISLBEvent{
+		Payload: &islb.ISLBEvent_Session{
+			Session: &ion.SessionEvent{
+				State: state,
+				Nid:   s.sn.NID,
+				Sid:   sid,
+				Peers: uint32(count),
+			},
+		},
+	})
+}
+
+func (s *sfuServer) lockSessionPost(sid string) *sessionPost {
+	s.mu.Lock()
+	post := s.posting[sid]
+	if post == nil {
+		post = &sessionPost{}
+		s.posting[sid] = post
+	}
+	post.refs++
+	s.mu.Unlock()
+
+	post.mu.Lock()
+	return post
+}
+
+func (s *sfuServer) unlockSessionPost(sid string, post *sessionPost) {
+	post.mu.Unlock()
 
+	s.mu.Lock()
+	post.refs--
+	if post.refs == 0 {
+		delete(s.posting, sid)
+	}
+	s.mu.Unlock()
+}
+
+// islbClient return the client the events are posted with
+func (s *sfuServer) islbClient() (islb.ISLBClient, error) {
+	s.islbLock.Lock()
+	defer s.islbLock.Unlock()
 	if s.islbcli == nil {
 		ncli, err := s.sn.NewNatsRPCClient(proto.ServiceISLB, "*", map[string]interface{}{})
 		if err != nil {
-			log.Errorf("NewNatsRPCClient err %v", err)
-			return
+			return nil, err
 		}
-		s.islbcli = islb.NewISLBClient(ncli)
+		s.islbcli, s.islbconn = islb.NewISLBClient(ncli), ncli
 	}
+	return s.islbcli, nil
+}
 
-	if s.islbcli != nil {
-		_, err := s.islbcli.PostISLBEvent(context.Background(), event)
-		if err != nil {
-			log.Errorf("PostISLBEvent err %v", err)
-		}
+// releaseISLBClient drop cli if it is still the client of the server, the
+// next event goes to the islb picked by the selector
+func (s *sfuServer) releaseISLBClient(cli islb.ISLBClient) {
+	s.islbLock.Lock()
+	defer s.islbLock.Unlock()
+	if cli == nil || s.islbcli != cli || s.islbconn == nil {
+		return
+	}
+	s.sn.Release(s.islbconn)
+	s.islbcli, s.islbconn = nil, nil
+}
+
+func (s *sfuServer) postISLBEvent(event *islb.ISLBEvent) {
+	cli, err := s.islbClient()
+	if err != nil {
+		log.Errorf("NewNatsRPCClient err %v", err)
+		return
+	}
+	if _, err := cli.PostISLBEvent(context.Background(), event); err != nil {
+		log.Errorf("PostISLBEvent err %v", err)
+		s.releaseISLBClient(cli)
+	}
+}
+
+// postStreamEvent tell the islb about the streams published by peer
+func (s *sfuServer) postStreamEvent(peer isfu.Peer, state ion.StreamEvent_State, streams []*ion.Stream) {
+	s.postISLBEvent(&islb.ISLBEvent{
+		Payload: &islb.ISLBEvent_Stream{
+			Stream: &ion.StreamEvent{
+				Nid:     s.sn.NID,
+				Sid:     peer.Session().ID(),
+				Uid:     peer.ID(),
+				State:   state,
+				Streams: streams,
+				Origin:  s.sn.NID,
+			},
+		},
+	})
+}
+
+// postStreamChanges tell the islb about the streams and tracks peer added,
+// removed or updated by going from the streams old to new.
+func (s *sfuServer) postStreamChanges(peer isfu.Peer, old, new []*ion.Stream) {
+	s.setPublishedTracks(peer, new)
+	added, removed, updated := util.DiffStreams(old, new)
+	if len(removed) > 0 {
+		s.postStreamEvent(peer, ion.StreamEvent_REMOVE, removed)
 	}
+	if len(added) > 0 {
+		s.postStreamEvent(peer, ion.StreamEvent_ADD, added)
+	}
+	if len(updated) > 0 {
+		s.postStreamEvent(peer, ion.StreamEvent_UPDATE, updated)
+	}
+}
+
+// withMetadata return streams with the metadata set by their publisher,
+// by stream id, streams is left unchanged.
+func withMetadata(streams []*ion.Stream, metadata map[string]map[string]string) []*ion.Stream {
+	var result []*ion.Stream
+	for _, stream := range streams {
+		result = append(result, &ion.Stream{
+			Id:       stream.Id,
+			Tracks:   stream.Tracks,
+			Metadata: metadata[stream.Id],
+		})
+	}
+	return result
+}
+
+// setMetadata set the metadata of the stream id in metadata and return the
+// streams published by peer with it. The metadata changes without
+// renegotiation, a stream not published yet gets it with its tracks.
+func (s *sfuServer) setMetadata(peer isfu.Peer, streams []*ion.Stream, metadata map[string]map[string]string, id string, md map[string]string) []*ion.Stream {
+	metadata[id] = md
+	newStreams := withMetadata(streams, metadata)
+	s.postStreamChanges(peer, streams, newStreams)
+	return newStreams
 }
 
 func (s *sfuServer) Signal(stream pb.SFU_SignalServer) error {
+	s.sn.StreamStarted()
+	defer s.sn.StreamEnded()
+
 	recvCandidates := []webrtc.ICECandidateInit{}
 	peer := isfu.NewPeer(s.sfu)
+	stats := newPeerState(peer)
 	var streams []*ion.Stream
+	// the metadata of the streams, set by the peer with or before them
+	metadata := make(map[string]map[string]string)
+	joined := ""
+	var config joinConfig
 
 	defer func() {
 		if peer.Session() != nil {
-			s.postISLBEvent(&islb.ISLBEvent{
-				Payload: &islb.ISLBEvent_Stream{
-					Stream: &ion.StreamEvent{
-						Nid:     s.sn.NID,
-						Sid:     peer.Session().ID(),
-						Uid:     peer.ID(),
-						State:   ion.StreamEvent_REMOVE,
-						Streams: streams,
-					},
-				},
-			})
+			s.postStreamEvent(peer, ion.StreamEvent_REMOVE, streams)
+		}
+		if joined != "" {
+			s.removePeerState(joined, stats)
+			s.updateSession(joined, -1)
 		}
 	}()
 
@@ -106,6 +306,20 @@ func (s *sfuServer) Signal(stream pb.SFU_SignalServer) error {
 				}
 			}
 
+			config, err = parseJoinConfig(payload.Join.Config)
+			if err != nil {
+				err = stream.Send(&pb.SignalReply{
+					Payload: &pb.SignalReply_Error{
+						Error: fmt.Errorf("join error: %w", err).Error(),
+					},
+				})
+				if err != nil {
+					log.Errorf("grpc send error: %v", err)
+					return status.Errorf(codes.Internal, err.Error())
+				}
+				continue
+			}
+
 			// Notify user of new ice candidate
 			peer.OnIceCandidate = func(candidate *webrtc.ICECandidateInit, target int) {
 				bytes, err := json.Marshal(candidate)
@@ -152,6 +366,7 @@ func (s *sfuServer) Signal(stream pb.SFU_SignalServer) error {
 			}
 
 			peer.OnICEConnectionStateChange = func(c webrtc.ICEConnectionState) {
+				stats.setICEState(c)
 				err = stream.Send(&pb.SignalReply{
 					Payload: &pb.SignalReply_IceConnectionState{
 						IceConnectionState: c.String(),
@@ -163,7 +378,7 @@ func (s *sfuServer) Signal(stream pb.SFU_SignalServer) error {
 				}
 			}
 
-			err = peer.Join(payload.Join.Sid, payload.Join.Uid)
+			err = peer.Join(payload.Join.Sid, payload.Join.Uid, config.peerConfig())
 			if err != nil {
 				switch err {
 				case isfu.ErrTransportExists:
@@ -181,16 +396,36 @@ func (s *sfuServer) Signal(stream pb.SFU_SignalServer) error {
 				default:
 					return status.Errorf(codes.Unknown, err.Error())
 				}
+			} else if joined == "" {
+				joined = payload.Join.Sid
+				s.addPeerState(joined, stats)
+				s.updateSession(joined, 1)
+				s.relayPeer(peer)
 			}
 
-			answer, err := peer.Answer(offer)
-			if err != nil {
-				return status.Errorf(codes.Internal, fmt.Sprintf("answer error: %v", err))
-			}
+			// a peer without publisher has no offer to answer
+			var marshalled []byte
+			if !config.NoPublish {
+				answer, err := peer.Answer(offer)
+				if err != nil {
+					return status.Errorf(codes.Internal, fmt.Sprintf("answer error: %v", err))
+				}
+				answer.SDP = config.applyAnswer(answer.SDP)
 
-			marshalled, err := json.Marshal(answer)
-			if err != nil {
-				return status.Errorf(codes.Internal, fmt.Sprintf("sdp marshal error: %v", err))
+				marshalled, err = json.Marshal(answer)
+				if err != nil {
+					return status.Errorf(codes.Internal, fmt.Sprintf("sdp marshal error: %v", err))
+				}
+
+				// the join offer may already carry streams
+				newStreams, err := util.ParseSDP(offer.SDP)
+				if err != nil {
+					log.Errorf("util.ParseSDP error: %v", err)
+				} else if peer.Session() != nil {
+					newStreams = withMetadata(newStreams, metadata)
+					s.postStreamChanges(peer, streams, newStreams)
+					streams = newStreams
+				}
 			}
 
 			// send answer
@@ -244,6 +479,7 @@ func (s *sfuServer) Signal(stream pb.SFU_SignalServer) error {
 						return status.Errorf(codes.Unknown, fmt.Sprintf("negotiate error: %v", err))
 					}
 				}
+				answer.SDP = config.applyAnswer(answer.SDP)
 
 				marshalled, err := json.Marshal(answer)
 				if err != nil {
@@ -271,21 +507,9 @@ func (s *sfuServer) Signal(stream pb.SFU_SignalServer) error {
 				newStreams, err := util.ParseSDP(sdp.SDP)
 				if err != nil {
 					log.Errorf("util.ParseSDP error: %v", err)
-				}
-
-				if len(newStreams) > 0 {
-					s.postISLBEvent(&islb.ISLBEvent{
-						Payload: &islb.ISLBEvent_Stream{
-							Stream: &ion.StreamEvent{
-								Nid:     s.sn.NID,
-								Sid:     peer.Session().ID(),
-								Uid:     peer.ID(),
-								Streams: newStreams,
-								State:   ion.StreamEvent_ADD,
-							},
-						},
-					})
-
+				} else {
+					newStreams = withMetadata(newStreams, metadata)
+					s.postStreamChanges(peer, streams, newStreams)
 					streams = newStreams
 				}
 
@@ -345,6 +569,23 @@ func (s *sfuServer) Signal(stream pb.SFU_SignalServer) error {
 					return status.Errorf(codes.Unknown, fmt.Sprintf("negotiate error: %v", err))
 				}
 			}
+
+		case *pb.SignalRequest_Metadata:
+			if payload.Metadata.StreamId == "" {
+				err = stream.Send(&pb.SignalReply{
+					Id: in.Id,
+					Payload: &pb.SignalReply_Error{
+						Error: "metadata without stream id",
+					},
+				})
+				if err != nil {
+					log.Errorf("grpc send error: %v", err)
+					return status.Errorf(codes.Internal, err.Error())
+				}
+				continue
+			}
+
+			streams = s.setMetadata(peer, streams, metadata, payload.Metadata.StreamId, payload.Metadata.Metadata)
 		}
 	}
 }
@@ -0,0 +1,84 @@
+package sfu
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/ion/proto/ion"
+	"github.com/pion/ion/proto/islb"
+	"github.com/tj/assert"
+	"google.golang.org/grpc"
+)
+
+// postedEvents an islb client keeping the events posted to it
+type postedEvents struct {
+	islb.ISLBClient
+	mu      sync.Mutex
+	events  []*ion.SessionEvent
+	streams []*ion.StreamEvent
+}
+
+func (p *postedEvents) PostISLBEvent(ctx context.Context, in *islb.ISLBEvent, opts ...grpc.CallOption) (*ion.Empty, error) {
+	// a slow islb lets the posts of concurrent joins overlap
+	time.Sleep(time.Millisecond)
+	p.mu.Lock()
+	if session := in.GetSession(); session != nil {
+		p.events = append(p.events, session)
+	}
+	if stream := in.GetStream(); stream != nil {
+		p.streams = append(p.streams, stream)
+	}
+	p.mu.Unlock()
+	return &ion.Empty{}, nil
+}
+
+func TestUpdateSessionOrder(t *testing.T) {
+	posted := &postedEvents{}
+	s := newSFUServer(NewSFU(nid), nil, conf)
+	s.islbcli = posted
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.updateSession("room", 1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, posted.events, 16)
+	assert.Equal(t, ion.SessionEvent_ADD, posted.events[0].State)
+	for i, event := range posted.events {
+		assert.Equal(t, uint32(i+1), event.Peers)
+	}
+
+	for i := 0; i < 16; i++ {
+		s.updateSession("room", -1)
+	}
+	assert.Equal(t, ion.SessionEvent_REMOVE, posted.events[31].State)
+	assert.Empty(t, s.posting)
+}
+
+func TestMeasureLoad(t *testing.T) {
+	s := newSFUServer(NewSFU(nid), nil, conf)
+
+	done := make(chan struct{})
+	go func() {
+		s.measureLoad(10 * time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	s.close()
+	s.close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("measureLoad still running")
+	}
+	// no peer, nothing received or sent
+	assert.Equal(t, 0, s.bandwidth)
+}
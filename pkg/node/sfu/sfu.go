@@ -1,16 +1,19 @@
 package sfu
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/cloudwebrtc/nats-discovery/pkg/discovery"
 	nrpc "github.com/cloudwebrtc/nats-grpc/pkg/rpc"
 	"github.com/cloudwebrtc/nats-grpc/pkg/rpc/reflection"
 	log "github.com/pion/ion-log"
+	"github.com/pion/ion-sfu/pkg/buffer"
 	"github.com/pion/ion-sfu/pkg/middlewares/datachannel"
 	isfu "github.com/pion/ion-sfu/pkg/sfu"
 	"github.com/pion/ion/pkg/ion"
 	"github.com/pion/ion/pkg/proto"
+	"github.com/pion/ion/proto/rtc"
 	pb "github.com/pion/ion/proto/sfu"
 )
 
@@ -25,6 +28,10 @@ type natsConf struct {
 
 type nodeConf struct {
 	NID string `mapstructure:"nid"`
+	// Secret signs the node info, shared by every node of the cluster
+	Secret string `mapstructure:"secret"`
+	// Capacity the limits advertised to the islb
+	Capacity ion.Capacity `mapstructure:"capacity"`
 }
 
 // Config defines parameters for the logger
@@ -74,14 +81,22 @@ func (s *SFU) Start(conf Config) error {
 		s.Close()
 		return err
 	}
+	s.Node.SetSecret(conf.Node.Secret)
+	s.Node.SetCapacity(conf.Node.Capacity)
 
+	// the stats read the buffers of the received tracks
+	if conf.BufferFactory == nil {
+		conf.BufferFactory = buffer.NewBufferFactory(conf.Router.MaxPacketTrack, isfu.Logger)
+	}
 	nsfu := isfu.NewSFU(conf.Config)
 	dc := nsfu.NewDatachannel(isfu.APIChannelLabel)
 	dc.Use(datachannel.SubscriberAPI)
 
-	s.s = newSFUServer(s, nsfu)
+	s.s = newSFUServer(s, nsfu, conf)
+	go s.s.measureLoad(loadInterval)
 	//grpc service
 	pb.RegisterSFUServer(s.Node.ServiceRegistrar(), s.s)
+	rtc.RegisterRTCServer(s.Node.ServiceRegistrar(), newRTCServer(s.s))
 
 	// Register reflection service on nats-rpc server.
 	reflection.Register(s.Node.ServiceRegistrar().(*nrpc.Server))
@@ -115,7 +130,17 @@ func (s *SFU) Start(conf Config) error {
 	return nil
 }
 
+// Drain stop taking new sessions and close the node once the peers are gone or ctx is done
+func (s *SFU) Drain(ctx context.Context) error {
+	err := s.Node.Drain(ctx)
+	s.Close()
+	return err
+}
+
 // Close all
 func (s *SFU) Close() {
+	if s.s != nil {
+		s.s.close()
+	}
 	s.Node.Close()
 }
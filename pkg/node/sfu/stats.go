@@ -0,0 +1,337 @@
+package sfu
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pion/ion-sfu/pkg/buffer"
+	isfu "github.com/pion/ion-sfu/pkg/sfu"
+	"github.com/pion/ion/proto/ion"
+	pb "github.com/pion/ion/proto/sfu"
+	"github.com/pion/webrtc/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultStatsInterval = time.Second
+	minStatsInterval     = 100 * time.Millisecond
+	// the interval the bandwidth of the node is measured at
+	loadInterval = time.Second
+)
+
+// peerState what the stats report about a peer joined to the node
+type peerState struct {
+	peer *isfu.PeerLocal
+
+	mu       sync.Mutex
+	iceState webrtc.ICEConnectionState
+	tracks   int
+	// the bytes received and sent at the previous stats, for the bitrates
+	bytes     uint64
+	sentBytes uint64
+	sampled   time.Time
+	// the bytes received and sent at the previous measure of the load
+	measuredBytes uint64
+	measured      bool
+}
+
+// streamStats the buffer of a track received from a peer
+type streamStats struct {
+	buffer.Stats
+	clockRate uint32
+}
+
+func newPeerState(peer *isfu.PeerLocal) *peerState {
+	return &peerState{peer: peer, iceState: webrtc.ICEConnectionStateNew}
+}
+
+func (p *peerState) setICEState(state webrtc.ICEConnectionState) {
+	p.mu.Lock()
+	p.iceState = state
+	p.mu.Unlock()
+}
+
+func (p *peerState) setStreams(streams []*ion.Stream) {
+	tracks := 0
+	for _, stream := range streams {
+		tracks += len(stream.Tracks)
+	}
+	p.mu.Lock()
+	p.tracks = tracks
+	p.mu.Unlock()
+}
+
+// read the buffers of the peer, return the bytes received from and sent to
+// the peer, p.mu is held
+func (p *peerState) read(buffers *buffer.Factory) (stats *pb.PeerStats, bytes, sentBytes uint64) {
+	stats = &pb.PeerStats{
+		Uid:                p.peer.ID(),
+		IceConnectionState: p.iceState.String(),
+		PublishedTracks:    uint32(p.tracks),
+	}
+	if publisher := p.peer.Publisher(); publisher != nil {
+		bytes = readStreamStats(receivedStreams(publisher.PeerConnection(), buffers), stats)
+	}
+	if subscriber := p.peer.Subscriber(); subscriber != nil {
+		downTracks := subscriber.DownTracks()
+		stats.SubscribedTracks = uint32(len(downTracks))
+		for _, dt := range downTracks {
+			// the counters the sender reports of the track carry
+			if sr := dt.CreateSenderReport(); sr != nil {
+				stats.PacketsSent += uint64(sr.PacketCount)
+				sentBytes += uint64(sr.OctetCount)
+			}
+		}
+	}
+	return stats, bytes, sentBytes
+}
+
+// stats read the buffers and transports of the peer, the bitrates are those
+// since the previous stats of the peer
+func (p *peerState) stats(buffers *buffer.Factory, now time.Time) *pb.PeerStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats, bytes, sentBytes := p.read(buffers)
+	if publisher := p.peer.Publisher(); publisher != nil {
+		stats.Rtt = nominatedRTT(publisher.PeerConnection().GetStats())
+	}
+	if subscriber := p.peer.Subscriber(); subscriber != nil {
+		stats.SubscriberRtt = nominatedRTT(subscriber.PeerConnection().GetStats())
+	}
+
+	if elapsed := now.Sub(p.sampled).Seconds(); !p.sampled.IsZero() && elapsed > 0 {
+		stats.Bitrate = kbps(p.bytes, bytes, elapsed)
+		stats.SentBitrate = kbps(p.sentBytes, sentBytes, elapsed)
+	}
+	p.bytes, p.sentBytes, p.sampled = bytes, sentBytes, now
+	return stats
+}
+
+// throughput the bytes received from and sent to the peer since the
+// previous measure of the load
+func (p *peerState) throughput(buffers *buffer.Factory) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, bytes, sentBytes := p.read(buffers)
+	total := bytes + sentBytes
+	var delta uint64
+	if p.measured && total >= p.measuredBytes {
+		delta = total - p.measuredBytes
+	}
+	p.measuredBytes, p.measured = total, true
+	return delta
+}
+
+// kbps the bitrate of a byte counter going from prev to cur in elapsed seconds
+func kbps(prev, cur uint64, elapsed float64) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return uint64(float64(cur-prev) * 8 / 1000 / elapsed)
+}
+
+// receivedStreams the buffers of the tracks pc receives, pion does not
+// report the rtp streams of a peer connection
+func receivedStreams(pc *webrtc.PeerConnection, buffers *buffer.Factory) []streamStats {
+	if buffers == nil {
+		return nil
+	}
+	var streams []streamStats
+	for _, receiver := range pc.GetReceivers() {
+		for _, track := range receiver.Tracks() {
+			buff := buffers.GetBuffer(uint32(track.SSRC()))
+			if buff == nil {
+				continue
+			}
+			streams = append(streams, streamStats{
+				Stats:     buff.GetStats(),
+				clockRate: track.Codec().ClockRate,
+			})
+		}
+	}
+	return streams
+}
+
+// readStreamStats fill stats with the received streams, return the bytes received
+func readStreamStats(streams []streamStats, stats *pb.PeerStats) uint64 {
+	var bytes uint64
+	var jitter float64
+	n := 0
+	for _, s := range streams {
+		bytes += s.TotalByte
+		stats.PacketsReceived += uint64(s.PacketCount)
+		// the packets expected and received at the last receiver report
+		stats.PacketsLost += int64(s.LastExpected) - int64(s.LastReceived)
+		if s.clockRate > 0 {
+			// the jitter of a buffer is in rtp timestamp units
+			jitter += s.Jitter / float64(s.clockRate) * 1000
+			n++
+		}
+	}
+	if n > 0 {
+		stats.Jitter = jitter / float64(n)
+	}
+	return bytes
+}
+
+// nominatedRTT the round trip time of the nominated candidate pair of report, in ms
+func nominatedRTT(report webrtc.StatsReport) float64 {
+	for _, s := range report {
+		if pair, ok := s.(webrtc.ICECandidatePairStats); ok && pair.Nominated {
+			return pair.CurrentRoundTripTime * 1000
+		}
+	}
+	return 0
+}
+
+// sessionStats sum the stats of the peers of the session sid
+func sessionStats(sid string, peers []*pb.PeerStats) *pb.SessionStats {
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Uid < peers[j].Uid })
+	stats := &pb.SessionStats{Sid: sid, Peers: peers}
+	for _, peer := range peers {
+		stats.Bitrate += peer.Bitrate
+		stats.PacketsReceived += peer.PacketsReceived
+		stats.PacketsLost += peer.PacketsLost
+		stats.PublishedTracks += peer.PublishedTracks
+		stats.SubscribedTracks += peer.SubscribedTracks
+	}
+	return stats
+}
+
+// addPeerState count the peer of the session sid in the stats
+func (s *sfuServer) addPeerState(sid string, p *peerState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.peers[sid] == nil {
+		s.peers[sid] = make(map[string]*peerState)
+	}
+	s.peers[sid][p.peer.ID()] = p
+}
+
+func (s *sfuServer) removePeerState(sid string, p *peerState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.peers[sid][p.peer.ID()] != p {
+		return
+	}
+	delete(s.peers[sid], p.peer.ID())
+	if len(s.peers[sid]) == 0 {
+		delete(s.peers, sid)
+	}
+}
+
+// setPublishedTracks update the tracks of peer in the stats
+func (s *sfuServer) setPublishedTracks(peer isfu.Peer, streams []*ion.Stream) {
+	session := peer.Session()
+	if session == nil {
+		return
+	}
+	s.mu.Lock()
+	p := s.peers[session.ID()][peer.ID()]
+	s.mu.Unlock()
+	if p != nil {
+		p.setStreams(streams)
+	}
+}
+
+// collectStats the stats of the session sid, or of every session of the node when empty
+func (s *sfuServer) collectStats(sid string) *pb.StatsReply {
+	s.mu.Lock()
+	peers := make(map[string][]*peerState)
+	for id, session := range s.peers {
+		if sid != "" && id != sid {
+			continue
+		}
+		for _, p := range session {
+			peers[id] = append(peers[id], p)
+		}
+	}
+	s.mu.Unlock()
+
+	// the transports are read without the server lock
+	now := time.Now()
+	reply := &pb.StatsReply{Nid: s.sn.NID}
+	for id, session := range peers {
+		var stats []*pb.PeerStats
+		for _, p := range session {
+			stats = append(stats, p.stats(s.conf.BufferFactory, now))
+		}
+		reply.Sessions = append(reply.Sessions, sessionStats(id, stats))
+	}
+	sort.Slice(reply.Sessions, func(i, j int) bool { return reply.Sessions[i].Sid < reply.Sessions[j].Sid })
+	return reply
+}
+
+// measureLoad publish the bitrate received and sent by the peers of the
+// node every interval, until the server is closed
+func (s *sfuServer) measureLoad(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			var peers []*peerState
+			for _, session := range s.peers {
+				for _, p := range session {
+					peers = append(peers, p)
+				}
+			}
+			s.mu.Unlock()
+
+			// the buffers are read without the server lock
+			var bytes uint64
+			for _, p := range peers {
+				bytes += p.throughput(s.conf.BufferFactory)
+			}
+
+			s.mu.Lock()
+			if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+				s.bandwidth = int(kbps(0, bytes, elapsed))
+			}
+			s.updateLoad()
+			s.mu.Unlock()
+			last = now
+		}
+	}
+}
+
+func (s *sfuServer) GetStats(ctx context.Context, req *pb.StatsRequest) (*pb.StatsReply, error) {
+	reply := s.collectStats(req.Sid)
+	if req.Sid != "" && len(reply.Sessions) == 0 {
+		return nil, status.Errorf(codes.NotFound, "session %v not found", req.Sid)
+	}
+	return reply, nil
+}
+
+// WatchStats send the stats every interval until the client leaves, a
+// watched session without peers is reported without sessions.
+func (s *sfuServer) WatchStats(req *pb.StatsRequest, stream pb.SFU_WatchStatsServer) error {
+	interval := time.Duration(req.Interval) * time.Millisecond
+	if interval == 0 {
+		interval = defaultStatsInterval
+	} else if interval < minStatsInterval {
+		interval = minStatsInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := stream.Send(s.collectStats(req.Sid)); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package sfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/ion-sfu/pkg/buffer"
+	isfu "github.com/pion/ion-sfu/pkg/sfu"
+	pb "github.com/pion/ion/proto/sfu"
+	"github.com/pion/webrtc/v3"
+	"github.com/pion/webrtc/v3/pkg/media"
+	"github.com/tj/assert"
+)
+
+func TestReadStreamStats(t *testing.T) {
+	streams := []streamStats{
+		{
+			Stats:     buffer.Stats{TotalByte: 1000, PacketCount: 100, LastExpected: 90, LastReceived: 88, Jitter: 96},
+			clockRate: 48000,
+		},
+		{
+			Stats:     buffer.Stats{TotalByte: 9000, PacketCount: 300, LastExpected: 280, LastReceived: 276, Jitter: 360},
+			clockRate: 90000,
+		},
+	}
+
+	var stats pb.PeerStats
+	bytes := readStreamStats(streams, &stats)
+	assert.Equal(t, uint64(10000), bytes)
+	assert.Equal(t, uint64(400), stats.PacketsReceived)
+	assert.Equal(t, int64(6), stats.PacketsLost)
+	assert.InDelta(t, 3, stats.Jitter, 1e-9)
+
+	// a subscriber only peer receives nothing
+	stats = pb.PeerStats{}
+	assert.Equal(t, uint64(0), readStreamStats(nil, &stats))
+	assert.Equal(t, 0.0, stats.Jitter)
+}
+
+func TestNominatedRTT(t *testing.T) {
+	report := webrtc.StatsReport{
+		"backup": webrtc.ICECandidatePairStats{CurrentRoundTripTime: 0.5},
+		"pair":   webrtc.ICECandidatePairStats{Nominated: true, CurrentRoundTripTime: 0.05},
+		"peer":   webrtc.PeerConnectionStats{},
+	}
+	assert.InDelta(t, 50, nominatedRTT(report), 1e-9)
+	assert.Equal(t, 0.0, nominatedRTT(webrtc.StatsReport{}))
+}
+
+func TestKbps(t *testing.T) {
+	assert.Equal(t, uint64(80), kbps(1000, 11000, 1))
+	// a counter reset
+	assert.Equal(t, uint64(0), kbps(11000, 1000, 1))
+}
+
+func TestSessionStats(t *testing.T) {
+	stats := sessionStats("room", []*pb.PeerStats{
+		{Uid: "bob", Bitrate: 500, PacketsReceived: 10, PacketsLost: 1, SubscribedTracks: 2},
+		{Uid: "alice", Bitrate: 1500, PacketsReceived: 30, PublishedTracks: 2, SubscribedTracks: 0},
+	})
+	assert.Equal(t, "room", stats.Sid)
+	assert.Equal(t, uint64(2000), stats.Bitrate)
+	assert.Equal(t, uint64(40), stats.PacketsReceived)
+	assert.Equal(t, int64(1), stats.PacketsLost)
+	assert.Equal(t, uint32(2), stats.PublishedTracks)
+	assert.Equal(t, uint32(2), stats.SubscribedTracks)
+	assert.Equal(t, "alice", stats.Peers[0].Uid)
+	assert.Equal(t, "bob", stats.Peers[1].Uid)
+}
+
+func TestPeerStatsPublisher(t *testing.T) {
+	c := conf.Config
+	c.BufferFactory = buffer.NewBufferFactory(500, isfu.Logger)
+	peer := isfu.NewPeer(isfu.NewSFU(c))
+	state := newPeerState(peer)
+
+	pub, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+	defer pub.Close()
+	track, err := webrtc.NewTrackLocalStaticSample(webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus}, "audio", "pion")
+	assert.NoError(t, err)
+	_, err = pub.AddTrack(track)
+	assert.NoError(t, err)
+
+	connected := make(chan struct{}, 1)
+	pub.OnICEConnectionStateChange(func(ice webrtc.ICEConnectionState) {
+		if ice == webrtc.ICEConnectionStateConnected {
+			select {
+			case connected <- struct{}{}:
+			default:
+			}
+		}
+	})
+	pub.OnICECandidate(func(candidate *webrtc.ICECandidate) {
+		if candidate != nil {
+			assert.NoError(t, peer.Trickle(candidate.ToJSON(), 0))
+		}
+	})
+	peer.OnIceCandidate = func(candidate *webrtc.ICECandidateInit, target int) {
+		if target == 0 {
+			assert.NoError(t, pub.AddICECandidate(*candidate))
+		}
+	}
+	assert.NoError(t, peer.Join("room", "alice"))
+	defer peer.Close()
+
+	offer, err := pub.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, pub.SetLocalDescription(offer))
+	answer, err := peer.Answer(offer)
+	assert.NoError(t, err)
+	assert.NoError(t, pub.SetRemoteDescription(*answer))
+
+	select {
+	case <-connected:
+	case <-time.After(10 * time.Second):
+		t.Fatal("publisher not connected")
+	}
+	start := time.Now()
+	state.stats(c.BufferFactory, start)
+	for time.Since(start) < time.Second {
+		assert.NoError(t, track.WriteSample(media.Sample{Data: make([]byte, 100), Duration: 20 * time.Millisecond}))
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	stats := state.stats(c.BufferFactory, time.Now())
+	assert.Equal(t, "alice", stats.Uid)
+	assert.True(t, stats.PacketsReceived > 0)
+	assert.True(t, stats.Bitrate > 0)
+	assert.Equal(t, uint32(0), stats.SubscribedTracks)
+}
@@ -18,6 +18,11 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// nodeServices the nodes serving the grpc services not named after their node service
+var nodeServices = map[string]string{
+	proto.ServiceRTC: proto.ServiceSFU,
+}
+
 type svcConf struct {
 	Services []string `mapstructure:"services"`
 }
@@ -55,7 +60,10 @@ type avpConf struct {
 }
 
 type nodeConf struct {
-	NID string `mapstructure:"nid"`
+	NID      string `mapstructure:"nid"`
+	Selector string `mapstructure:"selector"`
+	// Secret signs the node info, shared by every node of the cluster
+	Secret string `mapstructure:"secret"`
 }
 
 // Config for biz node
@@ -70,9 +78,10 @@ type Config struct {
 
 type Signal struct {
 	ion.Node
-	conf Config
-	nc   *nats.Conn
-	ndc  *dc.Client
+	conf   Config
+	nc     *nats.Conn
+	ndc    *dc.Client
+	closed util.AtomicBool
 }
 
 func NewSignal(conf Config) (*Signal, error) {
@@ -102,6 +111,8 @@ func (s *Signal) Start() error {
 		s.Close()
 		return err
 	}
+	s.Node.SetSecret(s.conf.Node.Secret)
+	s.Node.SetSelector(ion.NewSelector(s.conf.Node.Selector))
 	node := discovery.Node{
 		DC:      s.conf.Global.Dc,
 		Service: proto.ServiceSIG,
@@ -160,6 +171,11 @@ func (s *Signal) Director(ctx context.Context, fullMethodName string) (context.C
 		}
 	}
 
+	// let the client reconnect through another signal node.
+	if s.Draining() {
+		return ctx, nil, status.Errorf(codes.Unavailable, "signal node %v is draining", s.NID)
+	}
+
 	//Find service in neighbor nodes.
 	svcConf := s.conf.Signal.SVC
 	for _, svc := range svcConf.Services {
@@ -169,11 +185,22 @@ func (s *Signal) Director(ctx context.Context, fullMethodName string) (context.C
 			for key, value := range md {
 				parameters[key] = value[0]
 			}
-			cli, err := s.NewNatsRPCClient(svc, "*", parameters)
+			service := svc
+			if node, ok := nodeServices[svc]; ok {
+				service = node
+			}
+			cli, err := s.NewNatsRPCClient(service, "*", parameters)
 			if err != nil {
 				log.Errorf("failed to Get service [%v]: %v", svc, err)
 				return ctx, nil, status.Errorf(codes.Unavailable, "Service Unavailable: %v", err)
 			}
+			// the incoming ctx is done when the proxied call ends.
+			s.StreamStarted()
+			go func() {
+				<-ctx.Done()
+				s.Release(cli)
+				s.StreamEnded()
+			}()
 			return ctx, cli, nil
 		}
 	}
@@ -181,7 +208,18 @@ func (s *Signal) Director(ctx context.Context, fullMethodName string) (context.C
 	return ctx, nil, status.Errorf(codes.Unimplemented, "Unknown Service.Method %v", fullMethodName)
 }
 
+// Drain stop taking new sessions and close the node once the proxied calls end or ctx is done
+func (s *Signal) Drain(ctx context.Context) error {
+	err := s.Node.Drain(ctx)
+	s.Close()
+	return err
+}
+
 func (s *Signal) Close() {
+	if !s.closed.Set(true) {
+		return
+	}
+	s.Node.Close()
 	s.nc.Close()
 	s.ndc.Close()
 }
@@ -7,4 +7,6 @@ const (
 	ServiceSFU  = "sfu"
 	ServiceAVP  = "avp"
 	ServiceSIG  = "sig"
+	// ServiceRTC the rtc.RTC signalling, served by the sfu nodes
+	ServiceRTC = "rtc"
 )
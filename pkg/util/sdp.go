@@ -1,84 +1,303 @@
 package util
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 
-	log "github.com/pion/ion-log"
 	"github.com/pion/ion/proto/ion"
-	"github.com/pixelbender/go-sdp/sdp"
 )
 
-//ParseSDP .
-func ParseSDP(sdpstr string) ([]*ion.Stream, error) {
-	sess, err := sdp.ParseString(sdpstr)
+// the sdp directions
+const (
+	sdpSendRecv = "sendrecv"
+	sdpSendOnly = "sendonly"
+	sdpRecvOnly = "recvonly"
+	sdpInactive = "inactive"
+)
 
-	if err != nil {
-		log.Errorf("sdp.Parse erro %v", err)
-		return nil, err
-	}
+var errInvalidSDP = errors.New("invalid sdp")
 
-	streams := make(map[string]*ion.Stream)
-	for _, m := range sess.Media {
-		//fmt.Printf("type = %v\n", m.Type)
+// staticPayloadTypes the codecs of the static payload types, which may come without rtpmap
+var staticPayloadTypes = map[string]string{
+	"0":  "PCMU",
+	"8":  "PCMA",
+	"9":  "G722",
+	"18": "G729",
+}
 
-		if m.Type == "audio" || m.Type == "video" {
-			msid := m.Attributes.Get("msid")
-			//fmt.Printf("msid id = %v\n", msid)
+// sdpMedia a media section
+type sdpMedia struct {
+	kind      string
+	port      string
+	formats   []string
+	direction string
+	mid       string
+	msid      string
+	codecs    map[string]string
+	ssrcs     []uint32
+	// msid of the ssrc lines, plan-b
+	ssrcMsid  map[uint32]string
+	rids      []string
+	ridDirs   map[string]string
+	simulcast []*ion.SimulcastLayer
+	bundled   bool
+}
 
-			if msid == "" {
-				continue
-			}
+// ParseSDP return the streams sent by the peer of sdp, the tracks of a
+// unified plan sdp come from the msid of their media section, those of
+// a plan-b sdp from the msid of their ssrcs. The media sections without
+// msid, like the recvonly ones, carry no track.
+func ParseSDP(sdpstr string) ([]*ion.Stream, error) {
+	lines := strings.Split(strings.TrimSpace(sdpstr), "\n")
+	if !strings.HasPrefix(lines[0], "v=") {
+		return nil, errInvalidSDP
+	}
 
-			strs := strings.Split(msid, " ")
-			streamID := strs[0]
-			trackID := msid
-			trackLabel := strs[1]
+	direction := sdpSendRecv
+	var media []*sdpMedia
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if len(line) < 2 || line[1] != '=' {
+			continue
+		}
+		if strings.HasPrefix(line, "m=") {
+			m, err := parseMediaLine(line, direction)
+			if err != nil {
+				return nil, err
+			}
+			media = append(media, m)
+			continue
+		}
+		if !strings.HasPrefix(line, "a=") {
+			continue
+		}
+		name, value := splitAttribute(line[2:])
+		if len(media) == 0 {
+			// the session direction is the default of the media sections
+			if isDirection(name) {
+				direction = name
+			}
+			continue
+		}
+		media[len(media)-1].parseAttribute(name, value)
+	}
 
-			track := &ion.Track{
-				Kind:  m.Type,
-				Id:    trackID,
-				Label: trackLabel,
+	var list []*ion.Stream
+	streams := make(map[string]*ion.Stream)
+	for _, m := range media {
+		if m.kind != "audio" && m.kind != "video" {
+			continue
+		}
+		// a rejected or stopped section
+		if m.port == "0" && !m.bundled {
+			continue
+		}
+		for _, track := range m.tracks() {
+			streamID, _ := splitMsid(track.Id)
+			stream, ok := streams[streamID]
+			if !ok {
+				stream = &ion.Stream{Id: streamID}
+				streams[streamID] = stream
+				list = append(list, stream)
 			}
+			stream.Tracks = append(stream.Tracks, track)
+		}
+	}
+	return list, nil
+}
 
-			simulcast := make(map[string]string)
-			for _, attr := range m.Attributes {
-				//fmt.Printf("attr name = %v, value = %v\n", attr.Name, attr.Value)
+// parseMediaLine parse m=<media> <port>[/<number of ports>] <proto> <fmt> ...
+func parseMediaLine(line, direction string) (*sdpMedia, error) {
+	fields := strings.Fields(line[2:])
+	if len(fields) < 3 {
+		return nil, errInvalidSDP
+	}
+	return &sdpMedia{
+		kind:      fields[0],
+		port:      strings.Split(fields[1], "/")[0],
+		formats:   fields[3:],
+		direction: direction,
+		codecs:    make(map[string]string),
+		ssrcMsid:  make(map[uint32]string),
+		ridDirs:   make(map[string]string),
+	}, nil
+}
 
-				if attr.Name == "rid" {
-					strs := strings.Split(attr.Value, " ")
-					rid := strs[0]
-					dir := strs[1]
-					//fmt.Printf("rid: rid = %v, dir = %v\n", rid, dir)
-					simulcast[rid] = dir
-				}
-				/*
-					if attr.Name == "simulcast" {
-						strs := strings.Split(attr.Value, " ")
-						dir := strs[0]
-						rids := strs[1]
-						fmt.Printf("simulcast: rids = %v, dir = %v\n", rids, dir)
-					}
-				*/
-			}
-			track.Simulcast = simulcast
+func (m *sdpMedia) parseAttribute(name, value string) {
+	switch {
+	case isDirection(name):
+		m.direction = name
+	case name == "mid":
+		m.mid = value
+	case name == "msid":
+		m.msid = value
+	case name == "bundle-only":
+		m.bundled = true
+	case name == "rtpmap":
+		// <payload type> <encoding name>/<clock rate>[/<channels>]
+		strs := strings.Fields(value)
+		if len(strs) == 2 {
+			m.codecs[strs[0]] = strings.Split(strs[1], "/")[0]
+		}
+	case name == "ssrc":
+		// <ssrc> <attribute>[:<value>]
+		strs := strings.SplitN(value, " ", 2)
+		ssrc, err := strconv.ParseUint(strs[0], 10, 32)
+		if err != nil {
+			return
+		}
+		if _, known := m.ssrcMsid[uint32(ssrc)]; !known {
+			m.ssrcs = append(m.ssrcs, uint32(ssrc))
+			m.ssrcMsid[uint32(ssrc)] = ""
+		}
+		if len(strs) == 2 && strings.HasPrefix(strs[1], "msid:") {
+			m.ssrcMsid[uint32(ssrc)] = strings.TrimPrefix(strs[1], "msid:")
+		}
+	case name == "rid":
+		// <rid> <send|recv> [<restrictions>]
+		strs := strings.Fields(value)
+		if len(strs) >= 2 {
+			m.rids = append(m.rids, strs[0])
+			m.ridDirs[strs[0]] = strs[1]
+		}
+	case name == "simulcast":
+		m.simulcast = parseSimulcast(value)
+	}
+}
 
-			if stream, ok := streams[streamID]; ok {
-				stream.Tracks = append(stream.Tracks, track)
-			} else {
-				stream = &ion.Stream{
-					Id: streamID,
+// parseSimulcast parse <send|recv> <rids> [<send|recv> <rids>], the rids are
+// separated by ";", their alternatives by ",", a paused rid starts with "~".
+// The draft syntax "send rid=f;h;q" is accepted too.
+func parseSimulcast(value string) []*ion.SimulcastLayer {
+	var layers []*ion.SimulcastLayer
+	fields := strings.Fields(value)
+	for i := 0; i+1 < len(fields); i += 2 {
+		direction := fields[i]
+		for _, alternatives := range strings.Split(strings.TrimPrefix(fields[i+1], "rid="), ";") {
+			for _, rid := range strings.Split(alternatives, ",") {
+				paused := strings.HasPrefix(rid, "~")
+				rid = strings.TrimPrefix(rid, "~")
+				if rid != "" {
+					layers = append(layers, &ion.SimulcastLayer{Rid: rid, Direction: direction, Paused: paused})
 				}
-				stream.Tracks = append(stream.Tracks, track)
-				streams[streamID] = stream
 			}
 		}
 	}
+	return layers
+}
 
-	var list []*ion.Stream
-	for _, stream := range streams {
-		//fmt.Printf("%v\n", stream.String())
-		list = append(list, stream)
+// tracks return the tracks sent in the section
+func (m *sdpMedia) tracks() []*ion.Track {
+	if m.msid != "" {
+		return []*ion.Track{m.track(m.msid, m.ssrcs)}
 	}
 
-	return list, nil
+	// plan-b, a track by msid of the ssrcs
+	var tracks []*ion.Track
+	ssrcs := make(map[string][]uint32)
+	var msids []string
+	for _, ssrc := range m.ssrcs {
+		msid := m.ssrcMsid[ssrc]
+		if msid == "" {
+			continue
+		}
+		if _, ok := ssrcs[msid]; !ok {
+			msids = append(msids, msid)
+		}
+		ssrcs[msid] = append(ssrcs[msid], ssrc)
+	}
+	for _, msid := range msids {
+		tracks = append(tracks, m.track(msid, ssrcs[msid]))
+	}
+	return tracks
+}
+
+func (m *sdpMedia) track(msid string, ssrcs []uint32) *ion.Track {
+	_, label := splitMsid(msid)
+	if label == "" {
+		label = m.mid
+	}
+	track := &ion.Track{
+		Id:        msid,
+		Label:     label,
+		Kind:      m.kind,
+		Direction: m.direction,
+		// the peer stopped sending without removing the track
+		Muted:  m.direction == sdpRecvOnly || m.direction == sdpInactive,
+		Ssrcs:  ssrcs,
+		Layers: m.layers(),
+	}
+	if pt, codec := m.codec(); codec != "" {
+		track.Codec = codec
+		if n, err := strconv.ParseUint(pt, 10, 32); err == nil {
+			track.PayloadType = uint32(n)
+		}
+	}
+	if len(m.rids) > 0 {
+		track.Simulcast = make(map[string]string)
+		for _, rid := range m.rids {
+			track.Simulcast[rid] = m.ridDirs[rid]
+		}
+	}
+	return track
+}
+
+// codec return the preferred codec of the section, the first one
+// which is not a retransmission or error correction format
+func (m *sdpMedia) codec() (string, string) {
+	for _, pt := range m.formats {
+		codec, ok := m.codecs[pt]
+		if !ok {
+			codec = staticPayloadTypes[pt]
+		}
+		switch strings.ToLower(codec) {
+		case "", "rtx", "red", "ulpfec", "flexfec-03", "telephone-event", "cn":
+			continue
+		}
+		return pt, codec
+	}
+	return "", ""
+}
+
+// layers return the simulcast layers, from the simulcast attribute or the rids
+func (m *sdpMedia) layers() []*ion.SimulcastLayer {
+	if len(m.simulcast) > 0 {
+		return m.simulcast
+	}
+	var layers []*ion.SimulcastLayer
+	for _, rid := range m.rids {
+		layers = append(layers, &ion.SimulcastLayer{Rid: rid, Direction: m.ridDirs[rid]})
+	}
+	return layers
+}
+
+// splitAttribute split <name>[:<value>]
+func splitAttribute(attr string) (string, string) {
+	strs := strings.SplitN(attr, ":", 2)
+	if len(strs) == 1 {
+		return strs[0], ""
+	}
+	return strs[0], strs[1]
+}
+
+// splitMsid split <stream id> [<track id>]
+func splitMsid(msid string) (string, string) {
+	strs := strings.Fields(msid)
+	switch len(strs) {
+	case 0:
+		return "", ""
+	case 1:
+		return strs[0], ""
+	default:
+		return strs[0], strs[1]
+	}
+}
+
+func isDirection(name string) bool {
+	switch name {
+	case sdpSendRecv, sdpSendOnly, sdpRecvOnly, sdpInactive:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,130 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pion/ion/proto/ion"
+	"github.com/stretchr/testify/assert"
+)
+
+// readSDP read a browser sdp of testdata with its wire line endings
+func readSDP(t *testing.T, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	assert.NoError(t, err)
+	return strings.ReplaceAll(string(data), "\n", "\r\n")
+}
+
+// describe the tracks of streams as "<stream>/<label> <kind> <codec>/<pt> <direction> [muted] ssrcs=[...] layers=[...]"
+func describe(streams []*ion.Stream) []string {
+	var tracks []string
+	for _, stream := range streams {
+		for _, track := range stream.Tracks {
+			desc := fmt.Sprintf("%v/%v %v %v/%v %v", stream.Id, track.Label, track.Kind, track.Codec, track.PayloadType, track.Direction)
+			if track.Muted {
+				desc += " muted"
+			}
+			if len(track.Ssrcs) > 0 {
+				desc += fmt.Sprintf(" ssrcs=%v", track.Ssrcs)
+			}
+			if len(track.Layers) > 0 {
+				var layers []string
+				for _, layer := range track.Layers {
+					rid := layer.Direction + ":" + layer.Rid
+					if layer.Paused {
+						rid = "~" + rid
+					}
+					layers = append(layers, rid)
+				}
+				desc += fmt.Sprintf(" layers=%v", layers)
+			}
+			tracks = append(tracks, desc)
+		}
+	}
+	return tracks
+}
+
+func TestParseSDP(t *testing.T) {
+	tests := []struct {
+		name string
+		sdp  string
+		want []string
+		err  bool
+	}{
+		{
+			name: "chrome unified plan simulcast",
+			sdp:  readSDP(t, "chrome_unified_simulcast.sdp"),
+			want: []string{
+				"s5rpTrrL1Uka5cwcAZ9hzxDFF5WGVsuCHBj2/ceedd0d9-777c-45fd-9532-44fa800b67bc audio opus/111 sendonly ssrcs=[2527474376]",
+				"s5rpTrrL1Uka5cwcAZ9hzxDFF5WGVsuCHBj2/72ea9065-2399-4ab0-820b-8bcedfd030ab video VP8/96 sendonly layers=[send:f send:h send:q]",
+			},
+		},
+		{
+			name: "firefox",
+			sdp:  readSDP(t, "firefox.sdp"),
+			want: []string{
+				"{bca2b9e3-e934-4d13-a889-d8d93993d45f}/{635e5001-1079-415e-8e26-ec8346df7cd0} audio opus/109 sendrecv ssrcs=[2468515966]",
+				"{bca2b9e3-e934-4d13-a889-d8d93993d45f}/{7d1447cb-e669-4257-94e5-374908d78858} video VP8/120 sendrecv ssrcs=[1523161332 3084433161 1184622315 2927316085] layers=[send:h send:l]",
+			},
+		},
+		{
+			name: "chrome plan-b",
+			sdp:  readSDP(t, "chrome_planb.sdp"),
+			want: []string{
+				"CL3YvdcD9qZlQ4qhpQNp7HiaQ2YELb0kif7c/4a1f9e6b-3c28-4d57-b0e2-6f8a1c93d7e4 audio opus/111 sendrecv ssrcs=[1829376519]",
+				"CL3YvdcD9qZlQ4qhpQNp7HiaQ2YELb0kif7c/e2c07b58-91d4-4f36-a8b5-3d6e0f17c9a2 video VP8/100 sendrecv ssrcs=[2961704132 3605473979 1347220058 732914806]",
+				"zAyB5ZHTYsBxgQ8KfMwqXx4mF9o9ki5xAn7j/91c3e5a7-0d2f-4b84-9e61-c7a05f3b28d9 video VP8/100 sendrecv ssrcs=[4019382456 2210648793]",
+			},
+		},
+		{
+			name: "safari viewer",
+			sdp:  readSDP(t, "safari_recvonly.sdp"),
+		},
+		{
+			name: "msid without track id",
+			sdp: "v=0\r\ns=-\r\nt=0 0\r\n" +
+				"m=audio 9 UDP/TLS/RTP/SAVPF 0 8\r\na=mid:audio0\r\na=sendonly\r\na=msid:stream\r\n",
+			want: []string{"stream/audio0 audio PCMU/0 sendonly"},
+		},
+		{
+			name: "held and stopped tracks",
+			sdp: "v=0\r\ns=-\r\nt=0 0\r\na=inactive\r\n" +
+				"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\na=msid:stream mic\r\na=rtpmap:111 opus/48000/2\r\n" +
+				"m=video 0 UDP/TLS/RTP/SAVPF 96\r\na=sendonly\r\na=msid:stream cam\r\na=rtpmap:96 VP8/90000\r\n",
+			want: []string{"stream/mic audio opus/111 inactive muted"},
+		},
+		{
+			name: "draft simulcast",
+			sdp: "v=0\r\ns=-\r\nt=0 0\r\n" +
+				"m=video 9 UDP/TLS/RTP/SAVPF 96\r\na=msid:stream cam\r\na=rtpmap:96 VP8/90000\r\n" +
+				"a=rid:f send\r\na=rid:h send\r\na=simulcast: send rid=f;~h\r\n",
+			want: []string{"stream/cam video VP8/96 sendrecv layers=[send:f ~send:h]"},
+		},
+		{name: "empty", sdp: "", err: true},
+		{name: "not an sdp", sdp: "hello", err: true},
+		{name: "bad media line", sdp: "v=0\r\nm=audio\r\n", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streams, err := ParseSDP(tt.sdp)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, describe(streams))
+		})
+	}
+}
+
+func TestParseSDPSimulcast(t *testing.T) {
+	streams, err := ParseSDP(readSDP(t, "chrome_unified_simulcast.sdp"))
+	assert.NoError(t, err)
+	assert.Len(t, streams, 1)
+	video := streams[0].Tracks[1]
+	assert.Equal(t, "s5rpTrrL1Uka5cwcAZ9hzxDFF5WGVsuCHBj2 72ea9065-2399-4ab0-820b-8bcedfd030ab", video.Id)
+	assert.Equal(t, map[string]string{"f": "send", "h": "send", "q": "send"}, video.Simulcast)
+}
@@ -0,0 +1,160 @@
+package util
+
+import (
+	"github.com/pion/ion/proto/ion"
+	"google.golang.org/protobuf/proto"
+)
+
+// DiffStreams compare the streams of a publisher before and after a renegotiation
+// or a metadata change, return the streams with their new tracks, those with
+// their removed tracks, and those with the tracks whose settings or mute changed
+// or whose metadata changed. The added and updated streams carry their metadata.
+func DiffStreams(old, new []*ion.Stream) (added, removed, updated []*ion.Stream) {
+	oldTracks := trackIndex(old)
+	newTracks := trackIndex(new)
+	oldMetadata := make(map[string]map[string]string)
+	for _, stream := range old {
+		oldMetadata[stream.Id] = stream.Metadata
+	}
+
+	for _, stream := range new {
+		var add, update []*ion.Track
+		for _, track := range stream.Tracks {
+			prev, found := oldTracks[stream.Id][track.Id]
+			switch {
+			case !found:
+				add = append(add, track)
+			case !proto.Equal(prev, track):
+				update = append(update, track)
+			}
+		}
+		if len(add) > 0 {
+			added = append(added, &ion.Stream{Id: stream.Id, Tracks: add, Metadata: stream.Metadata})
+		}
+		_, known := oldTracks[stream.Id]
+		if len(update) > 0 || (known && !equalMetadata(oldMetadata[stream.Id], stream.Metadata)) {
+			updated = append(updated, &ion.Stream{Id: stream.Id, Tracks: update, Metadata: stream.Metadata})
+		}
+	}
+
+	for _, stream := range old {
+		var remove []*ion.Track
+		for _, track := range stream.Tracks {
+			if _, found := newTracks[stream.Id][track.Id]; !found {
+				remove = append(remove, track)
+			}
+		}
+		if len(remove) > 0 {
+			removed = append(removed, &ion.Stream{Id: stream.Id, Tracks: remove})
+		}
+	}
+	return
+}
+
+// ApplyStreamEvent return the streams of a publisher after the changes of an event,
+// the metadata of the ADD and UPDATE streams replace the one of their stream.
+// streams is left unchanged.
+func ApplyStreamEvent(streams []*ion.Stream, state ion.StreamEvent_State, changes []*ion.Stream) []*ion.Stream {
+	var result []*ion.Stream
+	for _, stream := range streams {
+		result = append(result, proto.Clone(stream).(*ion.Stream))
+	}
+
+	for _, change := range changes {
+		var stream *ion.Stream
+		for _, s := range result {
+			if s.Id == change.Id {
+				stream = s
+				break
+			}
+		}
+
+		switch state {
+		case ion.StreamEvent_ADD, ion.StreamEvent_UPDATE:
+			if stream == nil {
+				if state == ion.StreamEvent_UPDATE {
+					continue
+				}
+				stream = &ion.Stream{Id: change.Id}
+				result = append(result, stream)
+			}
+			stream.Metadata = copyMetadata(change.Metadata)
+			for _, track := range change.Tracks {
+				if i := trackPosition(stream, track.Id); i >= 0 {
+					stream.Tracks[i] = proto.Clone(track).(*ion.Track)
+				} else if state == ion.StreamEvent_ADD {
+					stream.Tracks = append(stream.Tracks, proto.Clone(track).(*ion.Track))
+				}
+			}
+		case ion.StreamEvent_REMOVE:
+			if stream == nil {
+				continue
+			}
+			for _, track := range change.Tracks {
+				if i := trackPosition(stream, track.Id); i >= 0 {
+					stream.Tracks = append(stream.Tracks[:i], stream.Tracks[i+1:]...)
+				}
+			}
+			// a stream goes with its last track
+			if len(stream.Tracks) == 0 || len(change.Tracks) == 0 {
+				result = removeStream(result, stream)
+			}
+		}
+	}
+	return result
+}
+
+// trackIndex the tracks of streams by stream id and track id
+func trackIndex(streams []*ion.Stream) map[string]map[string]*ion.Track {
+	index := make(map[string]map[string]*ion.Track)
+	for _, stream := range streams {
+		if index[stream.Id] == nil {
+			index[stream.Id] = make(map[string]*ion.Track)
+		}
+		for _, track := range stream.Tracks {
+			index[stream.Id][track.Id] = track
+		}
+	}
+	return index
+}
+
+func equalMetadata(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		c[k] = v
+	}
+	return c
+}
+
+func trackPosition(stream *ion.Stream, id string) int {
+	for i, track := range stream.Tracks {
+		if track.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeStream(streams []*ion.Stream, stream *ion.Stream) []*ion.Stream {
+	for i, s := range streams {
+		if s == stream {
+			return append(streams[:i], streams[i+1:]...)
+		}
+	}
+	return streams
+}
@@ -0,0 +1,159 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/pion/ion/proto/ion"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func track(id, kind string, muted bool) *ion.Track {
+	return &ion.Track{Id: id, Label: id, Kind: kind, Muted: muted}
+}
+
+func streamIDs(streams []*ion.Stream) map[string][]string {
+	ids := make(map[string][]string)
+	for _, stream := range streams {
+		for _, track := range stream.Tracks {
+			ids[stream.Id] = append(ids[stream.Id], track.Id)
+		}
+	}
+	return ids
+}
+
+func TestDiffStreams(t *testing.T) {
+	camera := &ion.Stream{Id: "camera", Tracks: []*ion.Track{track("mic", "audio", false), track("cam", "video", false)}}
+	screen := &ion.Stream{Id: "screen", Tracks: []*ion.Track{track("share", "video", false)}}
+	mutedCamera := &ion.Stream{Id: "camera", Tracks: []*ion.Track{track("mic", "audio", true), track("cam", "video", false)}}
+	cameraOnly := &ion.Stream{Id: "camera", Tracks: []*ion.Track{track("cam", "video", false)}}
+
+	tests := []struct {
+		name                    string
+		old, new                []*ion.Stream
+		added, removed, updated map[string][]string
+	}{
+		{
+			name:  "publish",
+			new:   []*ion.Stream{camera},
+			added: map[string][]string{"camera": {"mic", "cam"}},
+		},
+		{
+			name:  "start screen share",
+			old:   []*ion.Stream{camera},
+			new:   []*ion.Stream{camera, screen},
+			added: map[string][]string{"screen": {"share"}},
+		},
+		{
+			name:    "stop screen share",
+			old:     []*ion.Stream{camera, screen},
+			new:     []*ion.Stream{camera},
+			removed: map[string][]string{"screen": {"share"}},
+		},
+		{
+			name:    "mute",
+			old:     []*ion.Stream{camera},
+			new:     []*ion.Stream{mutedCamera},
+			updated: map[string][]string{"camera": {"mic"}},
+		},
+		{
+			name:    "unpublish a track",
+			old:     []*ion.Stream{camera},
+			new:     []*ion.Stream{cameraOnly},
+			removed: map[string][]string{"camera": {"mic"}},
+		},
+		{
+			name: "unchanged",
+			old:  []*ion.Stream{camera, screen},
+			new:  []*ion.Stream{screen, camera},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, updated := DiffStreams(tt.old, tt.new)
+			assert.Equal(t, tt.added, nilIfEmpty(streamIDs(added)))
+			assert.Equal(t, tt.removed, nilIfEmpty(streamIDs(removed)))
+			assert.Equal(t, tt.updated, nilIfEmpty(streamIDs(updated)))
+
+			// the events rebuild the new streams from the old ones
+			streams := ApplyStreamEvent(tt.old, ion.StreamEvent_REMOVE, removed)
+			streams = ApplyStreamEvent(streams, ion.StreamEvent_ADD, added)
+			streams = ApplyStreamEvent(streams, ion.StreamEvent_UPDATE, updated)
+			a, r, u := DiffStreams(tt.new, streams)
+			assert.Empty(t, a)
+			assert.Empty(t, r)
+			assert.Empty(t, u)
+		})
+	}
+}
+
+func TestApplyStreamEvent(t *testing.T) {
+	camera := &ion.Stream{Id: "camera", Tracks: []*ion.Track{track("mic", "audio", false), track("cam", "video", false)}}
+	streams := []*ion.Stream{camera}
+
+	// the last track takes its stream
+	removed := ApplyStreamEvent(streams, ion.StreamEvent_REMOVE, []*ion.Stream{
+		{Id: "camera", Tracks: []*ion.Track{track("mic", "audio", false)}},
+	})
+	assert.Equal(t, map[string][]string{"camera": {"cam"}}, streamIDs(removed))
+	removed = ApplyStreamEvent(removed, ion.StreamEvent_REMOVE, []*ion.Stream{
+		{Id: "camera", Tracks: []*ion.Track{track("cam", "video", false)}},
+	})
+	assert.Empty(t, removed)
+
+	// the tracks of an update must be known
+	updated := ApplyStreamEvent(streams, ion.StreamEvent_UPDATE, []*ion.Stream{
+		{Id: "camera", Tracks: []*ion.Track{track("mic", "audio", true), track("other", "video", false)}},
+		{Id: "screen", Tracks: []*ion.Track{track("share", "video", false)}},
+	})
+	assert.Equal(t, map[string][]string{"camera": {"mic", "cam"}}, streamIDs(updated))
+	assert.True(t, updated[0].Tracks[0].Muted)
+
+	// streams is left unchanged
+	assert.True(t, proto.Equal(track("mic", "audio", false), camera.Tracks[0]))
+	assert.Len(t, camera.Tracks, 2)
+}
+
+func TestStreamMetadata(t *testing.T) {
+	screen := &ion.Stream{Id: "screen", Tracks: []*ion.Track{track("share", "video", false)}}
+	labeled := &ion.Stream{Id: "screen", Tracks: screen.Tracks, Metadata: map[string]string{"type": "screen"}}
+	presenter := &ion.Stream{Id: "screen", Tracks: screen.Tracks, Metadata: map[string]string{"type": "screen", "role": "presenter"}}
+
+	// a stream is published with its metadata
+	added, removed, updated := DiffStreams(nil, []*ion.Stream{labeled})
+	assert.Len(t, added, 1)
+	assert.Equal(t, labeled.Metadata, added[0].Metadata)
+	assert.Empty(t, removed)
+	assert.Empty(t, updated)
+	streams := ApplyStreamEvent(nil, ion.StreamEvent_ADD, added)
+	assert.Equal(t, labeled.Metadata, streams[0].Metadata)
+
+	// and updated without tracks
+	added, removed, updated = DiffStreams(streams, []*ion.Stream{presenter})
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+	assert.Len(t, updated, 1)
+	assert.Empty(t, updated[0].Tracks)
+	streams = ApplyStreamEvent(streams, ion.StreamEvent_UPDATE, updated)
+	assert.Equal(t, presenter.Metadata, streams[0].Metadata)
+	assert.Equal(t, map[string][]string{"screen": {"share"}}, streamIDs(streams))
+
+	// or cleared
+	_, _, updated = DiffStreams(streams, []*ion.Stream{screen})
+	assert.Len(t, updated, 1)
+	streams = ApplyStreamEvent(streams, ion.StreamEvent_UPDATE, updated)
+	assert.Empty(t, streams[0].Metadata)
+
+	// the events do not share the metadata of their streams
+	updated[0].Metadata = map[string]string{"type": "camera"}
+	streams = ApplyStreamEvent(streams, ion.StreamEvent_UPDATE, updated)
+	updated[0].Metadata["type"] = "screen"
+	assert.Equal(t, "camera", streams[0].Metadata["type"])
+}
+
+func nilIfEmpty(m map[string][]string) map[string][]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
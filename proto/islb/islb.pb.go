@@ -138,6 +138,10 @@ type WatchRequest struct {
 
 	Nid string `protobuf:"bytes,1,opt,name=nid,proto3" json:"nid,omitempty"`
 	Sid string `protobuf:"bytes,2,opt,name=sid,proto3" json:"sid,omitempty"`
+	// replay the events after this seq, 0 for a snapshot of the current streams
+	ResumeAfter uint64 `protobuf:"varint,3,opt,name=resume_after,json=resumeAfter,proto3" json:"resume_after,omitempty"`
+	// the islb that numbered resume_after, a snapshot is sent if it is another one
+	ResumeIslb string `protobuf:"bytes,4,opt,name=resume_islb,json=resumeIslb,proto3" json:"resume_islb,omitempty"`
 }
 
 func (x *WatchRequest) Reset() {
@@ -186,6 +190,20 @@ func (x *WatchRequest) GetSid() string {
 	return ""
 }
 
+func (x *WatchRequest) GetResumeAfter() uint64 {
+	if x != nil {
+		return x.ResumeAfter
+	}
+	return 0
+}
+
+func (x *WatchRequest) GetResumeIslb() string {
+	if x != nil {
+		return x.ResumeIslb
+	}
+	return ""
+}
+
 type ISLBEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -195,6 +213,10 @@ type ISLBEvent struct {
 	//	*ISLBEvent_Session
 	//	*ISLBEvent_Stream
 	Payload isISLBEvent_Payload `protobuf_oneof:"payload"`
+	// monotonically increasing, shared by all the events of an islb
+	Seq uint64 `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	// the id of the islb process that numbered the event
+	Islb string `protobuf:"bytes,4,opt,name=islb,proto3" json:"islb,omitempty"`
 }
 
 func (x *ISLBEvent) Reset() {
@@ -250,6 +272,20 @@ func (x *ISLBEvent) GetStream() *ion.StreamEvent {
 	return nil
 }
 
+func (x *ISLBEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ISLBEvent) GetIslb() string {
+	if x != nil {
+		return x.Islb
+	}
+	return ""
+}
+
 type isISLBEvent_Payload interface {
 	isISLBEvent_Payload()
 }
@@ -280,27 +316,38 @@ var file_proto_islb_islb_proto_rawDesc = []byte{
 	0x69, 0x63, 0x65, 0x22, 0x30, 0x0a, 0x0d, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52,
 	0x65, 0x70, 0x6c, 0x79, 0x12, 0x1f, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20,
 	0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x69, 0x6f, 0x6e, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05,
-	0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x32, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x76, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x03, 0x6e, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64, 0x22, 0x71, 0x0a, 0x09, 0x49, 0x53, 0x4c,
-	0x42, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x07, 0x73, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61,
-	0x6d, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x32, 0x73, 0x0a, 0x04,
-	0x49, 0x53, 0x4c, 0x42, 0x12, 0x2e, 0x0a, 0x0d, 0x50, 0x6f, 0x73, 0x74, 0x49, 0x53, 0x4c, 0x42,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0f, 0x2e, 0x69, 0x73, 0x6c, 0x62, 0x2e, 0x49, 0x53, 0x4c,
-	0x42, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x1a, 0x0a, 0x2e, 0x69, 0x6f, 0x6e, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x53, 0x4c,
-	0x42, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x2e, 0x69, 0x73, 0x6c, 0x62, 0x2e, 0x57, 0x61,
-	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x69, 0x73, 0x6c,
-	0x62, 0x2e, 0x49, 0x53, 0x4c, 0x42, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x28, 0x01, 0x30,
-	0x01, 0x42, 0x20, 0x5a, 0x1e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x70, 0x69, 0x6f, 0x6e, 0x2f, 0x69, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x69,
-	0x73, 0x6c, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x73,
+	0x75, 0x6d, 0x65, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b,
+	0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x5f, 0x69, 0x73, 0x6c, 0x62, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x49, 0x73, 0x6c, 0x62, 0x22, 0x97, 0x01,
+	0x0a, 0x09, 0x49, 0x53, 0x4c, 0x42, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x69,
+	0x6f, 0x6e, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48,
+	0x00, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x69, 0x6f, 0x6e,
+	0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x06,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x73, 0x6c, 0x62,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x73, 0x6c, 0x62, 0x42, 0x09, 0x0a, 0x07,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x32, 0xad, 0x01, 0x0a, 0x04, 0x49, 0x53, 0x4c, 0x42,
+	0x12, 0x38, 0x0a, 0x08, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x15, 0x2e, 0x69,
+	0x73, 0x6c, 0x62, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x69, 0x73, 0x6c, 0x62, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4e,
+	0x6f, 0x64, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x2e, 0x0a, 0x0d, 0x50, 0x6f,
+	0x73, 0x74, 0x49, 0x53, 0x4c, 0x42, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0f, 0x2e, 0x69, 0x73,
+	0x6c, 0x62, 0x2e, 0x49, 0x53, 0x4c, 0x42, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x1a, 0x0a, 0x2e, 0x69,
+	0x6f, 0x6e, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x0e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x49, 0x53, 0x4c, 0x42, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x2e, 0x69,
+	0x73, 0x6c, 0x62, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0f, 0x2e, 0x69, 0x73, 0x6c, 0x62, 0x2e, 0x49, 0x53, 0x4c, 0x42, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x20, 0x5a, 0x1e, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x69, 0x6f, 0x6e, 0x2f, 0x69, 0x6f, 0x6e, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x69, 0x73, 0x6c, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -330,12 +377,14 @@ var file_proto_islb_islb_proto_depIdxs = []int32{
 	4, // 0: islb.FindNodeReply.nodes:type_name -> ion.Node
 	5, // 1: islb.ISLBEvent.session:type_name -> ion.SessionEvent
 	6, // 2: islb.ISLBEvent.stream:type_name -> ion.StreamEvent
-	3, // 3: islb.ISLB.PostISLBEvent:input_type -> islb.ISLBEvent
-	2, // 4: islb.ISLB.WatchISLBEvent:input_type -> islb.WatchRequest
-	7, // 5: islb.ISLB.PostISLBEvent:output_type -> ion.Empty
-	3, // 6: islb.ISLB.WatchISLBEvent:output_type -> islb.ISLBEvent
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
+	0, // 3: islb.ISLB.FindNode:input_type -> islb.FindNodeRequest
+	3, // 4: islb.ISLB.PostISLBEvent:input_type -> islb.ISLBEvent
+	2, // 5: islb.ISLB.WatchISLBEvent:input_type -> islb.WatchRequest
+	1, // 6: islb.ISLB.FindNode:output_type -> islb.FindNodeReply
+	7, // 7: islb.ISLB.PostISLBEvent:output_type -> ion.Empty
+	3, // 8: islb.ISLB.WatchISLBEvent:output_type -> islb.ISLBEvent
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
 	3, // [3:3] is the sub-list for extension type_name
 	3, // [3:3] is the sub-list for extension extendee
 	0, // [0:3] is the sub-list for field type_name
@@ -19,6 +19,7 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ISLBClient interface {
+	FindNode(ctx context.Context, in *FindNodeRequest, opts ...grpc.CallOption) (*FindNodeReply, error)
 	PostISLBEvent(ctx context.Context, in *ISLBEvent, opts ...grpc.CallOption) (*ion.Empty, error)
 	WatchISLBEvent(ctx context.Context, opts ...grpc.CallOption) (ISLB_WatchISLBEventClient, error)
 }
@@ -31,6 +32,15 @@ func NewISLBClient(cc grpc.ClientConnInterface) ISLBClient {
 	return &iSLBClient{cc}
 }
 
+func (c *iSLBClient) FindNode(ctx context.Context, in *FindNodeRequest, opts ...grpc.CallOption) (*FindNodeReply, error) {
+	out := new(FindNodeReply)
+	err := c.cc.Invoke(ctx, "/islb.ISLB/FindNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *iSLBClient) PostISLBEvent(ctx context.Context, in *ISLBEvent, opts ...grpc.CallOption) (*ion.Empty, error) {
 	out := new(ion.Empty)
 	err := c.cc.Invoke(ctx, "/islb.ISLB/PostISLBEvent", in, out, opts...)
@@ -75,6 +85,7 @@ func (x *iSLBWatchISLBEventClient) Recv() (*ISLBEvent, error) {
 // All implementations must embed UnimplementedISLBServer
 // for forward compatibility
 type ISLBServer interface {
+	FindNode(context.Context, *FindNodeRequest) (*FindNodeReply, error)
 	PostISLBEvent(context.Context, *ISLBEvent) (*ion.Empty, error)
 	WatchISLBEvent(ISLB_WatchISLBEventServer) error
 	mustEmbedUnimplementedISLBServer()
@@ -84,6 +95,9 @@ type ISLBServer interface {
 type UnimplementedISLBServer struct {
 }
 
+func (UnimplementedISLBServer) FindNode(context.Context, *FindNodeRequest) (*FindNodeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindNode not implemented")
+}
 func (UnimplementedISLBServer) PostISLBEvent(context.Context, *ISLBEvent) (*ion.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PostISLBEvent not implemented")
 }
@@ -103,6 +117,24 @@ func RegisterISLBServer(s grpc.ServiceRegistrar, srv ISLBServer) {
 	s.RegisterService(&ISLB_ServiceDesc, srv)
 }
 
+func _ISLB_FindNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ISLBServer).FindNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/islb.ISLB/FindNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ISLBServer).FindNode(ctx, req.(*FindNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ISLB_PostISLBEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ISLBEvent)
 	if err := dec(in); err != nil {
@@ -154,6 +186,10 @@ var ISLB_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "islb.ISLB",
 	HandlerType: (*ISLBServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindNode",
+			Handler:    _ISLB_FindNode_Handler,
+		},
 		{
 			MethodName: "PostISLBEvent",
 			Handler:    _ISLB_PostISLBEvent_Handler,
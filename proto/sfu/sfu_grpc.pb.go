@@ -19,6 +19,10 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SFUClient interface {
 	Signal(ctx context.Context, opts ...grpc.CallOption) (SFU_SignalClient, error)
+	Cascade(ctx context.Context, in *CascadeRequest, opts ...grpc.CallOption) (*CascadeReply, error)
+	Relay(ctx context.Context, in *RelayRequest, opts ...grpc.CallOption) (*RelayReply, error)
+	GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsReply, error)
+	WatchStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (SFU_WatchStatsClient, error)
 }
 
 type sFUClient struct {
@@ -60,11 +64,74 @@ func (x *sFUSignalClient) Recv() (*SignalReply, error) {
 	return m, nil
 }
 
+func (c *sFUClient) Cascade(ctx context.Context, in *CascadeRequest, opts ...grpc.CallOption) (*CascadeReply, error) {
+	out := new(CascadeReply)
+	err := c.cc.Invoke(ctx, "/sfu.SFU/Cascade", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sFUClient) Relay(ctx context.Context, in *RelayRequest, opts ...grpc.CallOption) (*RelayReply, error) {
+	out := new(RelayReply)
+	err := c.cc.Invoke(ctx, "/sfu.SFU/Relay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sFUClient) GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsReply, error) {
+	out := new(StatsReply)
+	err := c.cc.Invoke(ctx, "/sfu.SFU/GetStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sFUClient) WatchStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (SFU_WatchStatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SFU_ServiceDesc.Streams[1], "/sfu.SFU/WatchStats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sFUWatchStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SFU_WatchStatsClient interface {
+	Recv() (*StatsReply, error)
+	grpc.ClientStream
+}
+
+type sFUWatchStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *sFUWatchStatsClient) Recv() (*StatsReply, error) {
+	m := new(StatsReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SFUServer is the server API for SFU service.
 // All implementations must embed UnimplementedSFUServer
 // for forward compatibility
 type SFUServer interface {
 	Signal(SFU_SignalServer) error
+	Cascade(context.Context, *CascadeRequest) (*CascadeReply, error)
+	Relay(context.Context, *RelayRequest) (*RelayReply, error)
+	GetStats(context.Context, *StatsRequest) (*StatsReply, error)
+	WatchStats(*StatsRequest, SFU_WatchStatsServer) error
 	mustEmbedUnimplementedSFUServer()
 }
 
@@ -75,6 +142,18 @@ type UnimplementedSFUServer struct {
 func (UnimplementedSFUServer) Signal(SFU_SignalServer) error {
 	return status.Errorf(codes.Unimplemented, "method Signal not implemented")
 }
+func (UnimplementedSFUServer) Cascade(context.Context, *CascadeRequest) (*CascadeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cascade not implemented")
+}
+func (UnimplementedSFUServer) Relay(context.Context, *RelayRequest) (*RelayReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Relay not implemented")
+}
+func (UnimplementedSFUServer) GetStats(context.Context, *StatsRequest) (*StatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedSFUServer) WatchStats(*StatsRequest, SFU_WatchStatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStats not implemented")
+}
 func (UnimplementedSFUServer) mustEmbedUnimplementedSFUServer() {}
 
 // UnsafeSFUServer may be embedded to opt out of forward compatibility for this service.
@@ -114,13 +193,101 @@ func (x *sFUSignalServer) Recv() (*SignalRequest, error) {
 	return m, nil
 }
 
+func _SFU_Cascade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CascadeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SFUServer).Cascade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sfu.SFU/Cascade",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SFUServer).Cascade(ctx, req.(*CascadeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SFU_Relay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SFUServer).Relay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sfu.SFU/Relay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SFUServer).Relay(ctx, req.(*RelayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SFU_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SFUServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sfu.SFU/GetStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SFUServer).GetStats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SFU_WatchStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SFUServer).WatchStats(m, &sFUWatchStatsServer{stream})
+}
+
+type SFU_WatchStatsServer interface {
+	Send(*StatsReply) error
+	grpc.ServerStream
+}
+
+type sFUWatchStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *sFUWatchStatsServer) Send(m *StatsReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // SFU_ServiceDesc is the grpc.ServiceDesc for SFU service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var SFU_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "sfu.SFU",
 	HandlerType: (*SFUServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Cascade",
+			Handler:    _SFU_Cascade_Handler,
+		},
+		{
+			MethodName: "Relay",
+			Handler:    _SFU_Relay_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _SFU_GetStats_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Signal",
@@ -128,6 +295,11 @@ var SFU_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchStats",
+			Handler:       _SFU_WatchStats_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/sfu/sfu.proto",
 }